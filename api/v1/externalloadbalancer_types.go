@@ -25,8 +25,11 @@ SOFTWARE.
 package v1
 
 import (
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func init() {
@@ -35,7 +38,7 @@ func init() {
 
 // addKnownTypes registers the API types with the given scheme.
 func addKnownTypes(scheme *runtime.Scheme) error {
-	scheme.AddKnownTypes(GroupVersion, &ExternalLoadBalancer{}, &ExternalLoadBalancerList{})
+	scheme.AddKnownTypes(GroupVersion, &ExternalLoadBalancer{}, &ExternalLoadBalancerList{}, &LoadBalancerProvider{}, &LoadBalancerProviderList{})
 	metav1.AddToGroupVersion(scheme, GroupVersion)
 	return nil
 }
@@ -50,6 +53,7 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 // +kubebuilder:resource:singular="externalloadbalancer"
 // +kubebuilder:resource:shortName="elb"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Load Balancer is configured and ready"
 // +kubebuilder:printcolumn:name="VIP",type="string",JSONPath=".spec.vip",description="Load Balancer VIP"
 // +kubebuilder:printcolumn:name="Ports",type="string",JSONPath=".spec.ports",description="Load Balancer Ports"
 // +kubebuilder:printcolumn:name="Provider",type="string",JSONPath=".spec.provider.vendor",description="Load Balancer Provider Backend"
@@ -65,14 +69,24 @@ type ExternalLoadBalancer struct {
 }
 
 // ExternalLoadBalancerSpec is the spec of a LoadBalancer instance.
+// +kubebuilder:validation:XValidation:rule="has(self.providerref) != (has(self.provider) && has(self.provider.vendor))",message="either providerref or the provider vendor, host, port and creds must be set"
+// +kubebuilder:validation:XValidation:rule="has(self.ports) != has(self.serviceports)",message="exactly one of ports or serviceports must be set"
 type ExternalLoadBalancerSpec struct {
-	// Vip is the Virtual IP configured in  this LoadBalancer instance
+	// Vip is the Virtual IP configured in  this LoadBalancer instance. Can be an IPv4 or IPv6 address,
+	// or "*" on HAProxy to bind on all the addresses.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
-	// +kubebuilder:validation:MaxLength=15
+	// +kubebuilder:validation:MaxLength=45
 	Vip string `json:"vip"`
 
+	// SecondaryVip is an optional Virtual IP from the other address family than Vip for dual-stack clusters.
+	// A VIP and pools with the node addresses from the same family are created for each address. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=45
+	SecondaryVip string `json:"secondaryvip,omitempty"`
+
 	// Type is the node role type (master or infra) for the LoadBalancer instance
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +kubebuilder:validation:Optional
@@ -84,22 +98,225 @@ type ExternalLoadBalancerSpec struct {
 	// +kubebuilder:validation:Optional
 	NodeLabels map[string]string `json:"nodelabels,omitempty"`
 
-	// Ports is the ports exposed by this LoadBalancer instance
+	// NodeSelector is a label selector with matchLabels and matchExpressions for the nodes used in this
+	// LoadBalancer instance as an alternative to "type" and "nodelabels". When more than one is set, nodes
+	// must match all of them. Optional.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
-	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Optional
+	NodeSelector *metav1.LabelSelector `json:"nodeselector,omitempty"`
+
+	// ExcludeTaints are node taints that take the nodes out of the LoadBalancer pools. Cordoned nodes and nodes
+	// with the "node.kubernetes.io/exclude-from-external-load-balancers" label are always excluded. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	ExcludeTaints []NodeTaint `json:"excludetaints,omitempty"`
+
+	// NodeAddress selects the node address used for the pool members. Defaults to the node ExternalIP
+	// falling back to the InternalIP. The "lbconfig.carlosedp.com/node-address" node annotation overrides it. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	NodeAddress *NodeAddress `json:"nodeaddress,omitempty"`
+
+	// Ports is the ports exposed by this LoadBalancer instance using the same port on the nodes and the TCP protocol.
+	// Exactly one of ports or serviceports must be set.
+	// Deprecated: use ServicePorts to set the target port and protocol.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:MinItems=1
 	// +kubebuilder:validation:MaxItems=128
-	Ports []int `json:"ports"`
+	Ports []int `json:"ports,omitempty"`
+
+	// ServicePorts is the ports exposed by this LoadBalancer instance with their target port on the nodes and protocol.
+	// Exactly one of ports or serviceports must be set.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=128
+	ServicePorts []ServicePort `json:"serviceports,omitempty"`
+
+	// MinMembers is the minimum number of members kept in each pool. Removals that would leave the pool
+	// with fewer members are refused and the load balancer is marked as Degraded. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MinMembers int `json:"minmembers,omitempty"`
+
+	// MaxRemovalPercent is the maximum percentage of the configured members removed from a pool in a
+	// single reconcile. Larger removals are refused and the load balancer is marked as Degraded. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MaxRemovalPercent int `json:"maxremovalpercent,omitempty"`
+
+	// DrainPeriod is the time a pool member is kept disabled in the backend, draining the existing
+	// connections, before being removed. Members are removed right away if not set. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	DrainPeriod *metav1.Duration `json:"drainperiod,omitempty"`
+
+	// ResyncPeriod is the interval the backend configuration is read again to detect and correct drift.
+	// Defaults to the operator --sync-period flag. Set to 0 to disable the periodic resync. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	ResyncPeriod *metav1.Duration `json:"resyncperiod,omitempty"`
+
+	// Mode is how the operator applies the configuration to the backend. "Enforce" applies the changes
+	// and corrects drift, "ObserveOnly" applies the changes but only reports drift and "Plan" only reads
+	// the backend reporting the changes it would apply in the status. Defaults to "Enforce".
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Enforce;ObserveOnly;Plan
+	Mode string `json:"mode,omitempty"`
+
+	// DeletionPolicy is what happens to the backend configuration when the ExternalLoadBalancer is deleted.
+	// "Delete" removes the VIPs, pools, members and monitor while "Retain" keeps them running so they can be
+	// adopted by a new ExternalLoadBalancer with the same name. Defaults to "Delete".
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Retain;Delete
+	DeletionPolicy string `json:"deletionpolicy,omitempty"`
+
+	// Adopt allows the operator to take over existing VIPs and pools with the same names not owned by
+	// this ExternalLoadBalancer, like the ones created manually or by another cluster. Without it the
+	// operator refuses to modify them. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	Adopt bool `json:"adopt,omitempty"`
+
+	// NamingTemplate is the Go template of the backend object names with the .Kind (Monitor, Pool or VIP),
+	// .ClusterID, .Namespace, .Name and .Port fields. Defaults to the operator --naming-template flag.
+	// Existing objects are renamed when the template changes. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	NamingTemplate string `json:"namingtemplate,omitempty"`
 
 	// Monitor is the path and port to monitor the LoadBalancer members
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +kubebuilder:validation:Required
 	Monitor Monitor `json:"monitor"`
 
-	// Provider is the LoadBalancer backend provider
+	// Provider is the LoadBalancer backend provider. With providerref only the partition, validatecerts,
+	// debug and lbmethod can be set, overriding the LoadBalancerProvider defaults.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
-	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Optional
 	Provider Provider `json:"provider"`
+
+	// ProviderRef is the name of the cluster-scoped LoadBalancerProvider holding the backend provider
+	// connection details, used instead of the provider vendor, host, port and creds. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	ProviderRef string `json:"providerref,omitempty"`
+}
+
+// NodeTaint selects the node taints with the key and, if set, the value and effect
+type NodeTaint struct {
+	// Key is the taint key
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// Value is the taint value. Matches any value if not set.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	Value string `json:"value,omitempty"`
+
+	// Effect is the taint effect. Matches any effect if not set.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=NoSchedule;PreferNoSchedule;NoExecute
+	Effect string `json:"effect,omitempty"`
+}
+
+// NodeAddress defines how the node address used for the pool members is selected
+type NodeAddress struct {
+	// Types is the node address types in priority order. The first type with an address is used.
+	// Defaults to ExternalIP and InternalIP. Hostname addresses are used for both IP families and only on HAProxy.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:Enum=InternalIP;ExternalIP;Hostname
+	Types []string `json:"types,omitempty"`
+
+	// CIDRs keeps only the node addresses inside one of the CIDRs, like the load balancer data-plane network.
+	// Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	CIDRs []string `json:"cidrs,omitempty"`
+}
+
+// Node address types used in NodeAddress
+const (
+	NodeAddressInternalIP = "InternalIP"
+	NodeAddressExternalIP = "ExternalIP"
+	NodeAddressHostname   = "Hostname"
+)
+
+// ServicePort defines a port exposed by the LoadBalancer instance
+type ServicePort struct {
+	// Name is an optional name for this port
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name,omitempty"`
+
+	// Port is the port the VIP listens to
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int `json:"port"`
+
+	// TargetPort is the port on the nodes the traffic is forwarded to, like a NodePort. Defaults to Port.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	TargetPort int `json:"targetPort,omitempty"`
+
+	// Protocol is the port protocol. Defaults to "TCP".
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=TCP;UDP
+	// +kubebuilder:default=TCP
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// Modes of the ExternalLoadBalancer
+const (
+	ModeEnforce     = "Enforce"
+	ModeObserveOnly = "ObserveOnly"
+	ModePlan        = "Plan"
+)
+
+// Deletion policies of the ExternalLoadBalancer
+const (
+	DeletionPolicyRetain = "Retain"
+	DeletionPolicyDelete = "Delete"
+)
+
+// Port protocols supported by the ServicePort
+const (
+	ProtocolTCP = "TCP"
+	ProtocolUDP = "UDP"
+)
+
+// GetServicePorts returns the Ports and ServicePorts from the spec with the default target port and protocol set
+func (s *ExternalLoadBalancerSpec) GetServicePorts() []ServicePort {
+	ports := make([]ServicePort, 0, len(s.Ports)+len(s.ServicePorts))
+	for _, p := range s.Ports {
+		ports = append(ports, ServicePort{Port: p, TargetPort: p, Protocol: ProtocolTCP})
+	}
+	for _, p := range s.ServicePorts {
+		if p.TargetPort == 0 {
+			p.TargetPort = p.Port
+		}
+		if p.Protocol == "" {
+			p.Protocol = ProtocolTCP
+		}
+		ports = append(ports, p)
+	}
+	return ports
 }
 
 // Monitor defines a monitor object in the LoadBalancer.
@@ -109,11 +326,11 @@ type Monitor struct {
 	// +kubebuilder:validation:Optional
 	Name string `json:"name,omitempty"`
 
-	// Path is the path URL to check for the pool members in the format `/healthz`
+	// Path is the path URL to check for the pool members in the format `/healthz`. Required for http and https monitors.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
-	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:MinLength=1
-	Path string `json:"path"`
+	Path string `json:"path,omitempty"`
 
 	// Port is the port this monitor should check the pool members
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
@@ -124,45 +341,168 @@ type Monitor struct {
 
 	// MonitorType is the monitor parent type. <monitorType> must be one of "http", "https",
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
-	// "icmp".
+	// "icmp" or "tcp". The "tcp" monitor checks the connection to the port and optionally sends and expects a string.
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=http;https;icmp
+	// +kubebuilder:validation:Enum=http;https;icmp;tcp
 	MonitorType string `json:"monitortype"`
+
+	// Interval is the time in seconds between the monitor checks. Defaults to 5, or 1 on HAProxy.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3600
+	Interval int `json:"interval,omitempty"`
+
+	// Timeout is the time in seconds without a successful check before the member is marked down on F5 BigIP,
+	// defaulting to 16, and the time to wait for a check response on Citrix ADC, defaulting to 2.
+	// (F5 BigIP and Citrix ADC only)
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3600
+	Timeout int `json:"timeout,omitempty"`
+
+	// Rise is the number of successful checks before the member is marked up. Uses the provider default if not set.
+	// (HAProxy and Citrix ADC only)
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32
+	Rise int `json:"rise,omitempty"`
+
+	// Fall is the number of failed checks before the member is marked down. Uses the provider default if not set.
+	// (HAProxy and Citrix ADC only)
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32
+	Fall int `json:"fall,omitempty"`
+
+	// ExpectedStatus is the HTTP status code expected in the check response. Any response is accepted if not set.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=599
+	ExpectedStatus int `json:"expectedstatus,omitempty"`
+
+	// Receive is a string expected in the check response. On F5 BigIP it takes precedence over ExpectedStatus.
+	// For tcp monitors it is the string expected after sending the Send string.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=255
+	Receive string `json:"receive,omitempty"`
+
+	// Send is the string sent by tcp monitors after connecting. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=255
+	Send string `json:"send,omitempty"`
+
+	// Method is the HTTP method used in the check request. Defaults to "GET".
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=GET;HEAD;POST
+	// +kubebuilder:default=GET
+	Method string `json:"method,omitempty"`
+
+	// Host is the Host header sent in the check request. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=253
+	Host string `json:"host,omitempty"`
+
+	// SNI is the TLS server name sent in the https check request. Optional. (HAProxy only)
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=253
+	SNI string `json:"sni,omitempty"`
+
+	// Owner is the ownership tag with the cluster ID, namespace and name set in the LoadBalancer
+	// monitor by the controller. It's not part of the spec or status.
+	Owner string `json:"-"`
+}
+
+// Monitor types supported by the Monitor
+const (
+	MonitorTypeHTTP  = "http"
+	MonitorTypeHTTPS = "https"
+	MonitorTypeICMP  = "icmp"
+	MonitorTypeTCP   = "tcp"
+)
+
+// Monitor defaults used when the fields are not set
+const (
+	DefaultMonitorInterval = 5
+	DefaultMonitorTimeout  = 16
+	DefaultMonitorMethod   = "GET"
+	// DefaultCitrixADCMonitorTimeout is the Citrix ADC monitor response timeout
+	DefaultCitrixADCMonitorTimeout = 2
+)
+
+// WithDefaults returns a copy of the monitor with the default interval, timeout and method set
+func (m Monitor) WithDefaults() Monitor {
+	if m.Interval == 0 {
+		m.Interval = DefaultMonitorInterval
+	}
+	if m.Timeout == 0 {
+		m.Timeout = DefaultMonitorTimeout
+	}
+	if m.Method == "" {
+		m.Method = DefaultMonitorMethod
+	}
+	return m
 }
 
 // Provider is a backend provider for F5 Big IP Load Balancers
+// +kubebuilder:validation:XValidation:rule="has(self.vendor) && has(self.host) && has(self.port) && has(self.creds) || !has(self.vendor) && !has(self.host) && !has(self.port) && !has(self.creds)",message="vendor, host, port and creds must be set together"
 type Provider struct {
-	// Vendor is the backend provider vendor
+	// Vendor is the backend provider vendor. Required without providerref.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
-	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Enum=Dummy;F5_BigIP;Citrix_ADC;HAProxy
-	Vendor string `json:"vendor"`
+	Vendor string `json:"vendor,omitempty"`
 
-	// Host is the Load Balancer API IP or Hostname in URL format. Eg. `http://10.25.10.10`.
+	// Host is the Load Balancer API IP or Hostname in URL format. Eg. `http://10.25.10.10`. Required without providerref.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
-	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:MinLength=1
 	// +kubebuilder:validation:MaxLength=255
-	Host string `json:"host"`
+	Host string `json:"host,omitempty"`
 
-	// Port is the Load Balancer API Port.
+	// Port is the Load Balancer API Port. Required without providerref.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
-	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=65535
-	Port int `json:"port"`
+	Port int `json:"port,omitempty"`
 
-	// Creds is the credentials secret holding the "username" and "password" keys.
+	// Creds is the credentials secret holding the "username" and "password" keys. Required without providerref.
 	// Generate with: `kubectl create secret generic <secret-name> --from-literal=username=<username> --from-literal=password=<password>`
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
-	// +kubebuilder:validation:Required
-	Creds string `json:"creds"`
+	// +kubebuilder:validation:Optional
+	Creds string `json:"creds,omitempty"`
 
 	// Partition is the F5 partition to create the Load Balancer instances. Defaults to "Common". (F5 BigIP only)
+	// Deprecated: use f5.partition.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +kubebuilder:validation:Optional
 	Partition string `json:"partition,omitempty"`
 
+	// F5 is the F5 BigIP specific options. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	F5 *F5Options `json:"f5,omitempty"`
+
+	// CitrixADC is the Citrix ADC specific options. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	CitrixADC *CitrixADCOptions `json:"citrixADC,omitempty"`
+
+	// HAProxy is the HAProxy Dataplane API specific options. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	HAProxy *HAProxyOptions `json:"haproxy,omitempty"`
+
 	// ValidateCerts is a flag to validate or not the Load Balancer API certificate. Defaults to false.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +kubebuilder:validation:Enum=true;false
@@ -182,10 +522,101 @@ type Provider struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Enum=ROUNDROBIN;LEASTCONNECTION;LEASTRESPONSETIME
-	// +kubebuilder:default=ROUNDROBIN
 	LBMethod string `json:"lbmethod,omitempty"`
 }
 
+// F5Options is the F5 BigIP specific provider options
+type F5Options struct {
+	// Partition is the F5 partition to create the Load Balancer instances. Defaults to "Common".
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=64
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.-]+$`
+	Partition string `json:"partition,omitempty"`
+}
+
+// CitrixADCOptions is the Citrix ADC specific provider options
+type CitrixADCOptions struct {
+	// SaveConfig saves the running configuration in the Citrix ADC after the changes so they
+	// persist a reboot. Defaults to true.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	SaveConfig *bool `json:"saveconfig,omitempty"`
+}
+
+// HAProxyOptions is the HAProxy Dataplane API specific provider options
+type HAProxyOptions struct {
+	// BasePath is the Dataplane API base path. Defaults to "/v2".
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=255
+	// +kubebuilder:validation:Pattern=`^/[A-Za-z0-9/._-]*$`
+	BasePath string `json:"basepath,omitempty"`
+}
+
+// Provider options defaults used when the fields are not set
+const (
+	DefaultLBMethod        = "ROUNDROBIN"
+	DefaultF5Partition     = "Common"
+	DefaultHAProxyBasePath = "/v2"
+)
+
+// WithDefaults returns a copy of the provider with the default lbmethod set and the
+// deprecated partition moved to the f5 options
+func (p Provider) WithDefaults() Provider {
+	if p.LBMethod == "" {
+		p.LBMethod = DefaultLBMethod
+	}
+	if p.Partition != "" {
+		f5 := F5Options{}
+		if p.F5 != nil {
+			f5 = *p.F5
+		}
+		if f5.Partition == "" {
+			f5.Partition = p.Partition
+		}
+		p.F5 = &f5
+		p.Partition = ""
+	}
+	return p
+}
+
+// F5Partition returns the F5 partition, defaulting to "Common"
+func (p Provider) F5Partition() string {
+	if p.F5 != nil && p.F5.Partition != "" {
+		return p.F5.Partition
+	}
+	if p.Partition != "" {
+		return p.Partition
+	}
+	return DefaultF5Partition
+}
+
+// CitrixADCSaveConfig returns if the Citrix ADC configuration is saved after the changes, defaulting to true
+func (p Provider) CitrixADCSaveConfig() bool {
+	if p.CitrixADC != nil && p.CitrixADC.SaveConfig != nil {
+		return *p.CitrixADC.SaveConfig
+	}
+	return true
+}
+
+// HAProxyBasePath returns the HAProxy Dataplane API base path, defaulting to "/v2"
+func (p Provider) HAProxyBasePath() string {
+	if p.HAProxy != nil && p.HAProxy.BasePath != "" {
+		return p.HAProxy.BasePath
+	}
+	return DefaultHAProxyBasePath
+}
+
+// CredsSecret returns the credentials secret name. The secret is in the given namespace
+// unless Creds is in the "namespace/name" format used for the LoadBalancerProvider secrets.
+func (p Provider) CredsSecret(namespace string) types.NamespacedName {
+	if ns, name, ok := strings.Cut(p.Creds, "/"); ok {
+		return types.NamespacedName{Namespace: ns, Name: name}
+	}
+	return types.NamespacedName{Namespace: namespace, Name: p.Creds}
+}
+
 // Internal types
 
 // Pool defines a pool object in the LoadBalancer.
@@ -196,6 +627,10 @@ type Pool struct {
 	Members []PoolMember `json:"members,omitempty"`
 	// Monitor is the monitor name used on this pool
 	Monitor string `json:"monitor"`
+	// Protocol is the protocol (TCP or UDP) of this pool members
+	Protocol string `json:"protocol,omitempty"`
+	// Owner is the ownership tag with the cluster ID, namespace and name set in the LoadBalancer pool
+	Owner string `json:"owner,omitempty"`
 }
 
 // Node defines a host object in the LoadBalancer.
@@ -226,6 +661,20 @@ type VIP struct {
 	IP string `json:"ip"`
 	// Port is the port this VIP listens to
 	Port int `json:"port"`
+	// Protocol is the protocol (TCP or UDP) this VIP listens to
+	Protocol string `json:"protocol,omitempty"`
+	// Owner is the ownership tag with the cluster ID, namespace and name set in the LoadBalancer VIP
+	Owner string `json:"owner,omitempty"`
+}
+
+// DrainingMember is a pool member disabled in the LoadBalancer waiting for the drain period to be removed
+type DrainingMember struct {
+	// Pool is the pool name this member belongs to
+	Pool string `json:"pool"`
+	// Member is the pool member being drained
+	Member PoolMember `json:"member"`
+	// Since is the time the member was disabled
+	Since metav1.Time `json:"since"`
 }
 
 // ExternalLoadBalancerStatus defines the observed state of ExternalLoadBalancer
@@ -254,8 +703,45 @@ type ExternalLoadBalancerStatus struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	NumNodes int `json:"numnodes,omitempty"`
+	// DrainingMembers is the pool members disabled in the backend waiting for the drain period to be removed
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	DrainingMembers []DrainingMember `json:"drainingmembers,omitempty"`
+	// Drift is the differences found between the backend and the configuration last applied by the operator
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Drift []string `json:"drift,omitempty"`
+	// PlannedChanges is the changes the operator would apply to the backend in plan mode
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	PlannedChanges []string `json:"plannedchanges,omitempty"`
+	// ObservedGeneration is the most recent generation reconciled by the controller
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represent the latest observations of the ExternalLoadBalancer state
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:Optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// Condition types set in the ExternalLoadBalancer status
+const (
+	// ConditionReady indicates the load balancer is fully configured in the backend with members
+	ConditionReady = "Ready"
+	// ConditionBackendReachable indicates the backend provider API could be reached
+	ConditionBackendReachable = "BackendReachable"
+	// ConditionCredentialsValid indicates the provider credentials secret could be read
+	ConditionCredentialsValid = "CredentialsValid"
+	// ConditionSynced indicates the monitor, pools and VIPs were applied to the backend
+	ConditionSynced = "Synced"
+	// ConditionDegraded indicates the last reconcile failed, the load balancer has no members or a
+	// pool member removal was refused by MinMembers or MaxRemovalPercent
+	ConditionDegraded = "Degraded"
+	// ConditionDrifted indicates the backend configuration was changed outside of the operator
+	ConditionDrifted = "Drifted"
+	// ConditionPaused indicates the reconciliation is paused by the lbconfig.carlosedp.com/paused annotation
+	ConditionPaused = "Paused"
+)
+
 // +kubebuilder:object:root=true
 
 // ExternalLoadBalancerList contains a list of ExternalLoadBalancer
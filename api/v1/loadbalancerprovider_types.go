@@ -0,0 +1,221 @@
+/*
+MIT License
+
+Copyright (c) 2022 Carlos Eduardo de Paula
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+)
+
+// LoadBalancerProvider is the Schema for the loadbalancerproviders API. It holds the connection
+// details and defaults of a Load Balancer shared by the ExternalLoadBalancers referencing it.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +operator-sdk:csv:customresourcedefinitions:displayName="LoadBalancerProvider",resources={{Secret,v1,""},}
+// +kubebuilder:resource:path="loadbalancerproviders",scope=Cluster
+// +kubebuilder:resource:singular="loadbalancerprovider"
+// +kubebuilder:resource:shortName="lbp"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Vendor",type="string",JSONPath=".spec.vendor",description="Load Balancer Provider Backend"
+// +kubebuilder:printcolumn:name="Host",type="string",JSONPath=".spec.host",description="Load Balancer API Host"
+// +kubebuilder:printcolumn:name="Reachable",type="string",JSONPath=".status.conditions[?(@.type==\"Reachable\")].status",description="Load Balancer API is reachable"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="Load Balancer version"
+type LoadBalancerProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LoadBalancerProviderSpec   `json:"spec,omitempty"`
+	Status LoadBalancerProviderStatus `json:"status,omitempty"`
+}
+
+// LoadBalancerProviderSpec is the spec of a LoadBalancerProvider
+// +kubebuilder:validation:XValidation:rule="!has(self.f5) || self.vendor == 'F5_BigIP'",message="f5 options are only supported by the F5_BigIP vendor"
+// +kubebuilder:validation:XValidation:rule="!has(self.citrixADC) || self.vendor == 'Citrix_ADC'",message="citrixADC options are only supported by the Citrix_ADC vendor"
+// +kubebuilder:validation:XValidation:rule="!has(self.haproxy) || self.vendor == 'HAProxy'",message="haproxy options are only supported by the HAProxy vendor"
+type LoadBalancerProviderSpec struct {
+	// Vendor is the backend provider vendor
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=Dummy;F5_BigIP;Citrix_ADC;HAProxy
+	Vendor string `json:"vendor"`
+
+	// Host is the Load Balancer API IP or Hostname in URL format. Eg. `http://10.25.10.10`.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	Host string `json:"host"`
+
+	// Port is the Load Balancer API Port.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int `json:"port"`
+
+	// Creds is the credentials secret holding the "username" and "password" keys
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Required
+	Creds SecretReference `json:"creds"`
+
+	// F5 is the default F5 BigIP specific options. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	F5 *F5Options `json:"f5,omitempty"`
+
+	// CitrixADC is the default Citrix ADC specific options. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	CitrixADC *CitrixADCOptions `json:"citrixADC,omitempty"`
+
+	// HAProxy is the default HAProxy Dataplane API specific options. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	HAProxy *HAProxyOptions `json:"haproxy,omitempty"`
+
+	// ValidateCerts is a flag to validate or not the Load Balancer API certificate. Defaults to false.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	ValidateCerts bool `json:"validatecerts,omitempty"`
+
+	// Debug is a flag to enable debug on the backend log output. Defaults to false.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	Debug bool `json:"debug,omitempty"`
+
+	// LBMethod is the default Load-Balancing method. Defaults to "ROUNDROBIN".
+	// Options are: ROUNDROBIN, LEASTCONNECTION, LEASTRESPONSETIME
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=ROUNDROBIN;LEASTCONNECTION;LEASTRESPONSETIME
+	LBMethod string `json:"lbmethod,omitempty"`
+
+	// AllowedNamespaces selects the namespaces whose ExternalLoadBalancers can reference this
+	// LoadBalancerProvider by their labels. All namespaces are allowed if not set. Optional.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	AllowedNamespaces *metav1.LabelSelector `json:"allowednamespaces,omitempty"`
+}
+
+// SecretReference references a secret in a namespace
+type SecretReference struct {
+	// Name is the secret name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace is the secret namespace
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+}
+
+// LoadBalancerProviderStatus defines the observed state of LoadBalancerProvider
+type LoadBalancerProviderStatus struct {
+	// Version is the Load Balancer software version reported by the API
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Version string `json:"version,omitempty"`
+	// ObservedGeneration is the most recent generation reconciled by the controller
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represent the latest observations of the LoadBalancerProvider state
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:Optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Condition types set in the LoadBalancerProvider status
+const (
+	// ConditionReachable indicates the Load Balancer API could be reached with the credentials
+	ConditionReachable = "Reachable"
+)
+
+// Provider returns the ExternalLoadBalancer provider using this LoadBalancerProvider connection
+// details. The vendor options, validatecerts, debug and lbmethod set in the ExternalLoadBalancer
+// override the defaults. The credentials secret is returned as "namespace/name".
+func (l *LoadBalancerProvider) Provider(override Provider) Provider {
+	p := Provider{
+		Vendor:        l.Spec.Vendor,
+		Host:          l.Spec.Host,
+		Port:          l.Spec.Port,
+		Creds:         l.Spec.Creds.Namespace + "/" + l.Spec.Creds.Name,
+		ValidateCerts: l.Spec.ValidateCerts || override.ValidateCerts,
+		Debug:         l.Spec.Debug || override.Debug,
+		LBMethod:      l.Spec.LBMethod,
+		F5:            l.Spec.F5.DeepCopy(),
+		CitrixADC:     l.Spec.CitrixADC.DeepCopy(),
+		HAProxy:       l.Spec.HAProxy.DeepCopy(),
+	}
+	if override.LBMethod != "" {
+		p.LBMethod = override.LBMethod
+	}
+	// WithDefaults moves the deprecated partition to the f5 options
+	if f5 := override.WithDefaults().F5; f5 != nil && f5.Partition != "" {
+		if p.F5 == nil {
+			p.F5 = &F5Options{}
+		}
+		p.F5.Partition = f5.Partition
+	}
+	if override.CitrixADC != nil && override.CitrixADC.SaveConfig != nil {
+		if p.CitrixADC == nil {
+			p.CitrixADC = &CitrixADCOptions{}
+		}
+		p.CitrixADC.SaveConfig = ptr.To(*override.CitrixADC.SaveConfig)
+	}
+	if override.HAProxy != nil && override.HAProxy.BasePath != "" {
+		if p.HAProxy == nil {
+			p.HAProxy = &HAProxyOptions{}
+		}
+		p.HAProxy.BasePath = override.HAProxy.BasePath
+	}
+	return p.WithDefaults()
+}
+
+// AllowsNamespace returns true if the ExternalLoadBalancers in a namespace with the given
+// labels can reference this LoadBalancerProvider
+func (l *LoadBalancerProvider) AllowsNamespace(namespaceLabels map[string]string) (bool, error) {
+	if l.Spec.AllowedNamespaces == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(l.Spec.AllowedNamespaces)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(namespaceLabels)), nil
+}
+
+// +kubebuilder:object:root=true
+
+// LoadBalancerProviderList contains a list of LoadBalancerProvider
+type LoadBalancerProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LoadBalancerProvider `json:"items"`
+}
@@ -29,9 +29,47 @@ SOFTWARE.
 package v1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CitrixADCOptions) DeepCopyInto(out *CitrixADCOptions) {
+	*out = *in
+	if in.SaveConfig != nil {
+		in, out := &in.SaveConfig, &out.SaveConfig
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CitrixADCOptions.
+func (in *CitrixADCOptions) DeepCopy() *CitrixADCOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(CitrixADCOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainingMember) DeepCopyInto(out *DrainingMember) {
+	*out = *in
+	in.Member.DeepCopyInto(&out.Member)
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainingMember.
+func (in *DrainingMember) DeepCopy() *DrainingMember {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainingMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalLoadBalancer) DeepCopyInto(out *ExternalLoadBalancer) {
 	*out = *in
@@ -101,13 +139,43 @@ func (in *ExternalLoadBalancerSpec) DeepCopyInto(out *ExternalLoadBalancerSpec)
 			(*out)[key] = val
 		}
 	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExcludeTaints != nil {
+		in, out := &in.ExcludeTaints, &out.ExcludeTaints
+		*out = make([]NodeTaint, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeAddress != nil {
+		in, out := &in.NodeAddress, &out.NodeAddress
+		*out = new(NodeAddress)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Ports != nil {
 		in, out := &in.Ports, &out.Ports
 		*out = make([]int, len(*in))
 		copy(*out, *in)
 	}
+	if in.ServicePorts != nil {
+		in, out := &in.ServicePorts, &out.ServicePorts
+		*out = make([]ServicePort, len(*in))
+		copy(*out, *in)
+	}
+	if in.DrainPeriod != nil {
+		in, out := &in.DrainPeriod, &out.DrainPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	out.Monitor = in.Monitor
-	out.Provider = in.Provider
+	in.Provider.DeepCopyInto(&out.Provider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalLoadBalancerSpec.
@@ -148,7 +216,7 @@ func (in *ExternalLoadBalancerStatus) DeepCopyInto(out *ExternalLoadBalancerStat
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.Provider = in.Provider
+	in.Provider.DeepCopyInto(&out.Provider)
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
 		*out = make(map[string]string, len(*in))
@@ -156,6 +224,30 @@ func (in *ExternalLoadBalancerStatus) DeepCopyInto(out *ExternalLoadBalancerStat
 			(*out)[key] = val
 		}
 	}
+	if in.DrainingMembers != nil {
+		in, out := &in.DrainingMembers, &out.DrainingMembers
+		*out = make([]DrainingMember, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalLoadBalancerStatus.
@@ -168,6 +260,153 @@ func (in *ExternalLoadBalancerStatus) DeepCopy() *ExternalLoadBalancerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *F5Options) DeepCopyInto(out *F5Options) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new F5Options.
+func (in *F5Options) DeepCopy() *F5Options {
+	if in == nil {
+		return nil
+	}
+	out := new(F5Options)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HAProxyOptions) DeepCopyInto(out *HAProxyOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxyOptions.
+func (in *HAProxyOptions) DeepCopy() *HAProxyOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(HAProxyOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerProvider) DeepCopyInto(out *LoadBalancerProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerProvider.
+func (in *LoadBalancerProvider) DeepCopy() *LoadBalancerProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LoadBalancerProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerProviderList) DeepCopyInto(out *LoadBalancerProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LoadBalancerProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerProviderList.
+func (in *LoadBalancerProviderList) DeepCopy() *LoadBalancerProviderList {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerProviderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LoadBalancerProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerProviderSpec) DeepCopyInto(out *LoadBalancerProviderSpec) {
+	*out = *in
+	out.Creds = in.Creds
+	if in.F5 != nil {
+		in, out := &in.F5, &out.F5
+		*out = new(F5Options)
+		**out = **in
+	}
+	if in.CitrixADC != nil {
+		in, out := &in.CitrixADC, &out.CitrixADC
+		*out = new(CitrixADCOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HAProxy != nil {
+		in, out := &in.HAProxy, &out.HAProxy
+		*out = new(HAProxyOptions)
+		**out = **in
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerProviderSpec.
+func (in *LoadBalancerProviderSpec) DeepCopy() *LoadBalancerProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerProviderStatus) DeepCopyInto(out *LoadBalancerProviderStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerProviderStatus.
+func (in *LoadBalancerProviderStatus) DeepCopy() *LoadBalancerProviderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerProviderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Monitor) DeepCopyInto(out *Monitor) {
 	*out = *in
@@ -205,6 +444,46 @@ func (in *Node) DeepCopy() *Node {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeAddress) DeepCopyInto(out *NodeAddress) {
+	*out = *in
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAddress.
+func (in *NodeAddress) DeepCopy() *NodeAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeTaint) DeepCopyInto(out *NodeTaint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTaint.
+func (in *NodeTaint) DeepCopy() *NodeTaint {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeTaint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Pool) DeepCopyInto(out *Pool) {
 	*out = *in
@@ -246,6 +525,21 @@ func (in *PoolMember) DeepCopy() *PoolMember {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Provider) DeepCopyInto(out *Provider) {
 	*out = *in
+	if in.F5 != nil {
+		in, out := &in.F5, &out.F5
+		*out = new(F5Options)
+		**out = **in
+	}
+	if in.CitrixADC != nil {
+		in, out := &in.CitrixADC, &out.CitrixADC
+		*out = new(CitrixADCOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HAProxy != nil {
+		in, out := &in.HAProxy, &out.HAProxy
+		*out = new(HAProxyOptions)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Provider.
@@ -258,6 +552,36 @@ func (in *Provider) DeepCopy() *Provider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretReference) DeepCopyInto(out *SecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretReference.
+func (in *SecretReference) DeepCopy() *SecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServicePort) DeepCopyInto(out *ServicePort) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServicePort.
+func (in *ServicePort) DeepCopy() *ServicePort {
+	if in == nil {
+		return nil
+	}
+	out := new(ServicePort)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VIP) DeepCopyInto(out *VIP) {
 	*out = *in
@@ -31,6 +31,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -38,7 +39,10 @@ import (
 
 	"go.uber.org/zap/zapcore"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -57,6 +61,8 @@ import (
 
 	lbv1 "github.com/carlosedp/lbconfig-operator/api/v1"
 	controllers "github.com/carlosedp/lbconfig-operator/internal/controller"
+	backend "github.com/carlosedp/lbconfig-operator/internal/controller/backend/backend_controller"
+	webhookv1 "github.com/carlosedp/lbconfig-operator/internal/webhook/v1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -108,6 +114,11 @@ func main() {
 	var enableHTTP2 bool
 	var tlsOpts []func(*tls.Config)
 	var version bool
+	var eventInterval time.Duration
+	var syncPeriod time.Duration
+	var clusterID string
+	var namingTemplate string
+	var providerProbePeriod time.Duration
 	flag.BoolVar(&version, "version", false, "Prints the operator version")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -126,6 +137,18 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&eventInterval, "event-interval", 5*time.Minute,
+		"The minimum interval between identical Kubernetes events for an ExternalLoadBalancer.")
+	flag.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
+		"The default interval the backend configuration is resynced to detect and correct drift. Use 0 to disable.")
+	flag.StringVar(&clusterID, "cluster-id", "",
+		"The cluster ID set in the ownership tag of the backend objects. Defaults to the kube-system namespace UID.")
+	flag.StringVar(&namingTemplate, "naming-template", backend.DefaultNamingTemplate,
+		"The default Go template of the backend object names with the .Kind, .ClusterID, .Namespace, .Name and .Port fields. "+
+			"Add {{.ClusterID}}, the --cluster-id value, to share a Load Balancer between clusters, "+
+			"like '{{.Kind}}-{{.ClusterID}}-{{.Namespace}}-{{.Name}}{{if .Port}}-{{.Port}}{{end}}'.")
+	flag.DurationVar(&providerProbePeriod, "provider-probe-period", 5*time.Minute,
+		"The interval the LoadBalancerProvider API reachability and version are checked. Use 0 to disable.")
 
 	opts := zap.Options{
 		Development: true,
@@ -284,13 +307,48 @@ func main() {
 		os.Exit(1)
 	}
 
+	if clusterID == "" {
+		// The kube-system namespace UID identifies the cluster
+		ns := &corev1.Namespace{}
+		if err := mgr.GetAPIReader().Get(context.Background(), types.NamespacedName{Name: metav1.NamespaceSystem}, ns); err != nil {
+			setupLog.Error(err, "unable to get the cluster ID from the kube-system namespace, set it with --cluster-id")
+			os.Exit(1)
+		}
+		clusterID = string(ns.UID)
+	}
+	setupLog.Info("Using cluster ID for the backend objects ownership", "clusterID", clusterID)
+
+	if _, err := backend.NewNamer(namingTemplate, backend.NamingRules{}); err != nil {
+		setupLog.Error(err, "invalid --naming-template")
+		os.Exit(1)
+	}
+
 	if err := (&controllers.ExternalLoadBalancerReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Recorder:       controllers.NewRateLimitedRecorder(mgr.GetEventRecorder("lbconfig-operator"), eventInterval),
+		SyncPeriod:     syncPeriod,
+		ClusterID:      clusterID,
+		NamingTemplate: namingTemplate,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ExternalLoadBalancer")
 		os.Exit(1)
 	}
+	if err := (&controllers.LoadBalancerProviderReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		ProbePeriod: providerProbePeriod,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "LoadBalancerProvider")
+		os.Exit(1)
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookv1.SetupExternalLoadBalancerWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ExternalLoadBalancer")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -26,13 +26,21 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 
 	lbv1 "github.com/carlosedp/lbconfig-operator/api/v1"
@@ -41,7 +49,82 @@ import (
 // Tracer name
 const name = "github.com/carlosedp/lbconfig-operator"
 
+// Pool member status passed to EditPoolMember
+const (
+	MemberStatusEnable  = "enable"
+	MemberStatusDisable = "disable"
+)
+
+// Reasons of the events emitted for the backend changes
+const (
+	ReasonMonitorCreated = "MonitorCreated"
+	ReasonMonitorUpdated = "MonitorUpdated"
+	ReasonMonitorDeleted = "MonitorDeleted"
+	ReasonPoolCreated    = "PoolCreated"
+	ReasonPoolUpdated    = "PoolUpdated"
+	ReasonPoolDeleted    = "PoolDeleted"
+	ReasonMemberAdded    = "MemberAdded"
+	ReasonMemberRemoved  = "MemberRemoved"
+	ReasonMemberDisabled = "MemberDisabled"
+	ReasonMemberEnabled  = "MemberEnabled"
+	ReasonVIPCreated     = "VIPCreated"
+	ReasonVIPUpdated     = "VIPUpdated"
+	ReasonVIPDeleted     = "VIPDeleted"
+	ReasonCleanupFailed  = "CleanupFailed"
+	ReasonDriftDetected  = "DriftDetected"
+	ReasonAdopted        = "Adopted"
+)
+
+// ProviderV2 is the context-aware provider interface. Every call receives the
+// reconcile context so cancellation, deadlines and tracing reach the backend API calls.
+type ProviderV2 interface {
+	// Create a new backend provider
+	Create(context.Context, lbv1.Provider, string, string) error
+	// Connect initializes a connection to the backend provider
+	Connect(context.Context) error
+	// Close closes the connection to the backend provider
+	Close(context.Context) error
+
+	// GetMonitor returns a monitor if it exists, with the Owner tag if the provider stores it
+	GetMonitor(context.Context, *lbv1.Monitor) (*lbv1.Monitor, error)
+	// CreateMonitor creates a new monitor
+	CreateMonitor(context.Context, *lbv1.Monitor) error
+	// EditMonitor updates a monitor
+	EditMonitor(context.Context, *lbv1.Monitor) error
+	// DeleteMonitor deletes a monitor
+	DeleteMonitor(context.Context, *lbv1.Monitor) error
+
+	// GetPool returns a pool if it exists
+	GetPool(context.Context, *lbv1.Pool) (*lbv1.Pool, error)
+	// CreatePool creates a new pool
+	CreatePool(context.Context, *lbv1.Pool) error
+	// EditPool updates a pool
+	EditPool(context.Context, *lbv1.Pool) error
+	// DeletePool deletes a pool
+	DeletePool(context.Context, *lbv1.Pool) error
+	// GetPoolMembers returns a pool members if it exists
+	GetPoolMembers(context.Context, *lbv1.Pool) (*lbv1.Pool, error)
+	// CreatePoolMember returns a pool if it exists
+	CreatePoolMember(context.Context, *lbv1.PoolMember, *lbv1.Pool) error
+	// EditPoolMember updates a pool member
+	EditPoolMember(context.Context, *lbv1.PoolMember, *lbv1.Pool, string) error
+	// DeletePoolMember deletes a pool member
+	DeletePoolMember(context.Context, *lbv1.PoolMember, *lbv1.Pool) error
+
+	// GetVIP returns a virtual server if it exists
+	GetVIP(context.Context, *lbv1.VIP) (*lbv1.VIP, error)
+	// CreateVIP creates a new virtual server
+	CreateVIP(context.Context, *lbv1.VIP) error
+	// EditVIP updates a virtual server
+	EditVIP(context.Context, *lbv1.VIP) error
+	// DeleteVIP deletes a virtual server
+	DeleteVIP(context.Context, *lbv1.VIP) error
+}
+
 // Provider interface method signatures
+//
+// Deprecated: implement ProviderV2 and register it with RegisterProviderV2.
+// Providers implementing this interface are wrapped by AdaptProvider.
 type Provider interface {
 	// Create a new backend provider
 	Create(context.Context, lbv1.Provider, string, string) error
@@ -50,7 +133,8 @@ type Provider interface {
 	// Close closes the connection to the backend provider
 	Close() error
 
-	// GetMonitor returns a monitor if it exists
+	// GetMonitor returns a monitor if it exists. Fields not supported by the provider are
+	// copied from the requested monitor so they are not reported as changed
 	GetMonitor(*lbv1.Monitor) (*lbv1.Monitor, error)
 	// CreateMonitor creates a new monitor
 	CreateMonitor(*lbv1.Monitor) error
@@ -86,13 +170,128 @@ type Provider interface {
 	DeleteVIP(*lbv1.VIP) error
 }
 
+// Versioner is implemented by the providers that can report the Load Balancer software version.
+// It's used to check the LoadBalancerProvider reachability without changing the backend.
+type Versioner interface {
+	// Version returns the Load Balancer software version
+	Version(context.Context) (string, error)
+}
+
+// WriteOnlyMonitor is implemented by the providers that can't read the monitors back from the
+// Load Balancer. Their monitors are applied on every reconcile and never reported as drift.
+type WriteOnlyMonitor interface {
+	// WriteOnlyMonitor marks the provider monitors as write only
+	WriteOnlyMonitor()
+}
+
+// UntaggedMonitor is implemented by the providers that can't store the ownership tag on the
+// monitors. Their untagged monitors are adopted like the ones retained by a deleted instance.
+type UntaggedMonitor interface {
+	// UntaggedMonitor marks the provider monitors as not tagged with the owner
+	UntaggedMonitor()
+}
+
+// ErrVersionNotSupported is returned by Version for the providers not implementing Versioner
+var ErrVersionNotSupported = errors.New("provider doesn't report the Load Balancer version")
+
+// ProviderFactory returns a new, unconfigured, Provider instance.
+// A new instance is created for each backend so concurrent reconciles don't share state.
+type ProviderFactory func() Provider
+
+// ProviderV2Factory returns a new, unconfigured, ProviderV2 instance.
+type ProviderV2Factory func() ProviderV2
+
 // ExternalLoadBalancerReconciler reconciles a ExternalLoadBalancer object
 type BackendController struct {
-	log      logr.Logger
-	Provider Provider
+	log          logr.Logger
+	Provider     ProviderV2
+	RemovalGuard RemovalGuard
+	// DrainPeriod is the time removed pool members are kept disabled before being deleted
+	DrainPeriod time.Duration
+	// Draining is the pool members being drained. It's loaded from and saved to the
+	// ExternalLoadBalancer status by the reconciler.
+	Draining []lbv1.DrainingMember
+	// Recorder emits the events for the backend changes regarding the Regarding object. Optional.
+	Recorder  events.EventRecorder
+	Regarding runtime.Object
+	// Applied is the configuration applied in the previous reconcile. Backend objects differing
+	// from it while it matches the requested configuration are reported as drift. Optional.
+	Applied *lbv1.ExternalLoadBalancerStatus
+	// ObserveOnly reports the drift without correcting it
+	ObserveOnly bool
+	// Drift is the drift found in the backend by the Handle methods
+	Drift []string
+	// Owner is the ownership tag set in the pools and VIPs. Existing pools and VIPs not in the
+	// applied configuration are only modified if tagged with it or if Adopt is set. Optional.
+	Owner string
+	// Adopt allows taking over existing pools and VIPs not owned by the load balancer
+	Adopt bool
+}
+
+// RemovalGuard limits the pool members removed by HandlePool so an empty or
+// partial node list doesn't blackhole the traffic. Zero values disable the checks.
+type RemovalGuard struct {
+	// MinMembers is the minimum number of members kept in a pool
+	MinMembers int
+	// MaxRemovalPercent is the maximum percentage of the configured members removed at once
+	MaxRemovalPercent int
+}
+
+// MemberRemovalRefusedError is returned by HandlePool when the RemovalGuard refused
+// to remove pool members. Member additions and pool updates are still applied.
+type MemberRemovalRefusedError struct {
+	Pool       string
+	Configured int
+	Removed    int
+	Reason     string
+	// Members are the members kept in the pool, the requested ones and the ones not removed
+	Members []lbv1.PoolMember
+}
+
+func (e *MemberRemovalRefusedError) Error() string {
+	return fmt.Sprintf("refused to remove %d of %d members from pool %s: %s", e.Removed, e.Configured, e.Pool, e.Reason)
+}
+
+// NotOwnedError is returned by HandlePool and HandleVIP when an existing backend object
+// is not owned by the load balancer and adopting it is not allowed.
+type NotOwnedError struct {
+	Kind  string
+	Name  string
+	Owner string
 }
 
-var providers = make(map[string]Provider)
+func (e *NotOwnedError) Error() string {
+	owner := e.Owner
+	if owner == "" {
+		owner = "no owner"
+	}
+	return fmt.Sprintf("refused to modify %s %s owned by %s, set adopt to take it over", e.Kind, e.Name, owner)
+}
+
+// OwnerTag returns the ownership tag set in the backend objects created for the
+// ExternalLoadBalancer with the namespace and name in the cluster
+func OwnerTag(clusterID string, namespace string, name string) string {
+	return fmt.Sprintf("lbconfig-operator:%s/%s/%s", clusterID, namespace, name)
+}
+
+// check returns a MemberRemovalRefusedError if removing members from the configured
+// pool members, leaving the desired members, is not allowed by the guard.
+func (g RemovalGuard) check(pool string, configured int, desired int, removed int) error {
+	if removed == 0 {
+		return nil
+	}
+	if g.MinMembers > 0 && desired < g.MinMembers {
+		return &MemberRemovalRefusedError{Pool: pool, Configured: configured, Removed: removed,
+			Reason: fmt.Sprintf("the pool would have %d members, less than the minimum of %d", desired, g.MinMembers)}
+	}
+	if g.MaxRemovalPercent > 0 && removed*100 > g.MaxRemovalPercent*configured {
+		return &MemberRemovalRefusedError{Pool: pool, Configured: configured, Removed: removed,
+			Reason: fmt.Sprintf("more than %d%% of the members would be removed", g.MaxRemovalPercent)}
+	}
+	return nil
+}
+
+var providers = make(map[string]ProviderV2Factory)
 
 func ListProviders() []string {
 	p := make([]string, 0, len(providers))
@@ -102,7 +301,14 @@ func ListProviders() []string {
 	return p
 }
 
-func RegisterProvider(name string, provider Provider) error {
+// RegisterProvider registers a legacy provider factory with the vendor name used in the ExternalLoadBalancer spec.
+// The created providers are wrapped with AdaptProvider.
+func RegisterProvider(name string, factory ProviderFactory) error {
+	return RegisterProviderV2(name, func() ProviderV2 { return AdaptProvider(factory()) })
+}
+
+// RegisterProviderV2 registers a provider factory with the vendor name used in the ExternalLoadBalancer spec
+func RegisterProviderV2(name string, factory ProviderV2Factory) error {
 	name_slug := strings.ToLower(name)
 	var ctx = context.Background()
 	log := ctrllog.FromContext(ctx)
@@ -110,7 +316,7 @@ func RegisterProvider(name string, provider Provider) error {
 		return fmt.Errorf("provider already exists, provider '%s' tried to register twice", name)
 	}
 	log.Info("Registering provider", "provider", name)
-	providers[name_slug] = provider
+	providers[name_slug] = factory
 	return nil
 }
 
@@ -121,9 +327,10 @@ func CreateBackend(ctx context.Context, lbBackend *lbv1.Provider, username strin
 	backend := &BackendController{}
 	backend.log = ctrllog.FromContext(ctx)
 	name := strings.ToLower(lbBackend.Vendor)
-	if provider, ok := providers[name]; ok {
+	if factory, ok := providers[name]; ok {
+		provider := factory()
 		err := func(ctx context.Context) error {
-			_, span := otel.Tracer(name).Start(ctx, "Provider - Create")
+			ctx, span := otel.Tracer(name).Start(ctx, "Provider - Create")
 			defer span.End()
 			return provider.Create(ctx, *lbBackend, username, password)
 		}(ctx)
@@ -138,6 +345,44 @@ func CreateBackend(ctx context.Context, lbBackend *lbv1.Provider, username strin
 	return nil, fmt.Errorf("no such provider: %s. Available vendor providers are %s", name, ListProviders())
 }
 
+// Version returns the backend Load Balancer software version. It doesn't require
+// Connect so no transaction is opened or configuration saved in the backend.
+func (b *BackendController) Version(ctx context.Context) (string, error) {
+	var span trace.Span
+	ctx, span = otel.Tracer(name).Start(ctx, "Provider - Version")
+	defer span.End()
+
+	v, ok := unwrapProvider(b.Provider).(Versioner)
+	if !ok {
+		return "", ErrVersionNotSupported
+	}
+	return v.Version(ctx)
+}
+
+// writeOnlyMonitor returns true if the provider can't read the monitors back from the backend
+func (b *BackendController) writeOnlyMonitor() bool {
+	_, ok := unwrapProvider(b.Provider).(WriteOnlyMonitor)
+	return ok
+}
+
+// untaggedMonitor returns if the provider monitors can't be tagged with the owner
+func (b *BackendController) untaggedMonitor() bool {
+	_, ok := unwrapProvider(b.Provider).(UntaggedMonitor)
+	return ok
+}
+
+// unwrapProvider returns the provider wrapped by the plan mode and the legacy provider
+// adapter so its optional interfaces can be checked
+func unwrapProvider(provider ProviderV2) any {
+	if p, ok := provider.(*planProvider); ok {
+		provider = p.provider
+	}
+	if a, ok := provider.(*providerAdapter); ok {
+		return a.provider
+	}
+	return provider
+}
+
 // HandleMonitors manages the Monitor validation, update and creation
 func (b *BackendController) HandleMonitors(ctx context.Context, monitor *lbv1.Monitor) error {
 	var span trace.Span
@@ -147,10 +392,10 @@ func (b *BackendController) HandleMonitors(ctx context.Context, monitor *lbv1.Mo
 
 	// Check if monitor exists
 	m, err := func(ctx context.Context) (*lbv1.Monitor, error) {
-		_, span := otel.Tracer(name).Start(ctx, "Provider - GetMonitor")
+		ctx, span := otel.Tracer(name).Start(ctx, "Provider - GetMonitor")
 		span.SetAttributes(attribute.String("monitor.name", monitor.Name))
 		defer span.End()
-		return b.Provider.GetMonitor(monitor)
+		return b.Provider.GetMonitor(ctx, monitor)
 	}(ctx)
 
 	// Error getting monitor
@@ -158,27 +403,58 @@ func (b *BackendController) HandleMonitors(ctx context.Context, monitor *lbv1.Mo
 		return fmt.Errorf("error getting monitor: %s", err)
 	}
 
+	// The monitor can't be compared with the backend so it's always applied. In plan mode
+	// it's only planned when it differs from the applied monitor.
+	if b.writeOnlyMonitor() {
+		if b.planning() && b.Applied != nil && b.Applied.Monitor.Name == monitor.Name && !monitorChanged(monitor, &b.Applied.Monitor) {
+			return nil
+		}
+		span.SetAttributes(attribute.Bool("monitor.update", true))
+		return func(ctx context.Context) error {
+			ctx, span := otel.Tracer(name).Start(ctx, "Provider - EditMonitor")
+			span.SetAttributes(attribute.String("monitor.name", monitor.Name))
+			defer span.End()
+			return b.Provider.EditMonitor(ctx, monitor)
+		}(ctx)
+	}
+
 	// Monitor is not empty so update it's data if needed
 	if m != nil {
 		// Exists, so check to Update Monitor ports and parameters
 		b.log.Info("Monitor exists, check if needs update", "name", m.Name)
 		span.SetAttributes(attribute.Bool("monitor.exists", true))
-		if monitor.Port != m.Port || monitor.Path != m.Path || monitor.MonitorType != m.MonitorType {
+		applied := b.Applied != nil && b.Applied.Monitor.Name == monitor.Name
+		owner := m.Owner
+		if owner == "" && b.untaggedMonitor() {
+			owner = b.Owner
+		}
+		if err := b.claim("monitor", monitor.Name, owner, applied); err != nil {
+			return err
+		}
+		// Adopted monitors are updated to be tagged with the owner
+		if monitorChanged(monitor, m) || (!applied && m.Owner != monitor.Owner) {
+			if applied && !monitorChanged(monitor, &b.Applied.Monitor) {
+				b.drift("monitor %s configuration differs from the backend", monitor.Name)
+				if b.ObserveOnly {
+					return nil
+				}
+			}
 			b.log.Info("Monitor requires update", "name", monitor.Name)
 			b.log.Info("Need", "params", monitor)
 			b.log.Info("Have", "params", m)
 			span.SetAttributes(attribute.Bool("monitor.update", true))
 
 			err := func(ctx context.Context) error {
-				_, span := otel.Tracer(name).Start(ctx, "Provider - EditMonitor")
+				ctx, span := otel.Tracer(name).Start(ctx, "Provider - EditMonitor")
 				span.SetAttributes(attribute.String("monitor.name", m.Name))
 				defer span.End()
-				return b.Provider.EditMonitor(monitor)
+				return b.Provider.EditMonitor(ctx, monitor)
 			}(ctx)
 			if err != nil {
 				return err
 			}
 			b.log.Info("Monitor updated successfully", "name", monitor.Name)
+			b.event(corev1.EventTypeNormal, ReasonMonitorUpdated, "EditMonitor", "Updated monitor %s", monitor.Name)
 		} else {
 			span.SetAttributes(attribute.Bool("monitor.update", false))
 			b.log.Info("Monitor does not need update", "name", m.Name)
@@ -186,22 +462,48 @@ func (b *BackendController) HandleMonitors(ctx context.Context, monitor *lbv1.Mo
 		return nil
 	}
 
+	if b.Applied != nil && b.Applied.Monitor.Name == monitor.Name {
+		b.drift("monitor %s is missing in the backend", monitor.Name)
+		if b.ObserveOnly {
+			return nil
+		}
+	}
+
 	// Create Monitor
 	b.log.Info("Monitor does not exist. Creating...", "name", monitor.Name)
 	span.SetAttributes(attribute.Bool("monitor.exists", false))
 	err = func(ctx context.Context) error {
-		_, span := otel.Tracer(name).Start(ctx, "Provider - CreateMonitor")
+		ctx, span := otel.Tracer(name).Start(ctx, "Provider - CreateMonitor")
 		span.SetAttributes(attribute.String("monitor.name", monitor.Name))
 		defer span.End()
-		return b.Provider.CreateMonitor(monitor)
+		return b.Provider.CreateMonitor(ctx, monitor)
 	}(ctx)
 	if err != nil {
 		return err
 	}
 	b.log.Info("Created monitor", "name", monitor.Name, "port", monitor.Port)
+	b.event(corev1.EventTypeNormal, ReasonMonitorCreated, "CreateMonitor", "Created monitor %s", monitor.Name)
 	return nil
 }
 
+// monitorChanged returns true if the monitor configured in the backend differs from the requested monitor
+func monitorChanged(w *lbv1.Monitor, h *lbv1.Monitor) bool {
+	// The unset fields are compared with the defaults the providers apply
+	want, have := w.WithDefaults(), h.WithDefaults()
+	return want.Port != have.Port ||
+		want.Path != have.Path ||
+		want.MonitorType != have.MonitorType ||
+		want.Interval != have.Interval ||
+		want.Timeout != have.Timeout ||
+		want.Rise != have.Rise ||
+		want.Fall != have.Fall ||
+		want.ExpectedStatus != have.ExpectedStatus ||
+		want.Receive != have.Receive ||
+		want.Method != have.Method ||
+		want.Host != have.Host ||
+		want.SNI != have.SNI
+}
+
 // HandlePool manages the Pool validation, update and creation
 func (b *BackendController) HandlePool(ctx context.Context, pool *lbv1.Pool, monitor *lbv1.Monitor) error {
 	var span trace.Span
@@ -211,10 +513,10 @@ func (b *BackendController) HandlePool(ctx context.Context, pool *lbv1.Pool, mon
 
 	// Check if pool exists
 	p, err := func(ctx context.Context) (*lbv1.Pool, error) {
-		_, span := otel.Tracer(name).Start(ctx, "Provider - GetPool")
+		ctx, span := otel.Tracer(name).Start(ctx, "Provider - GetPool")
 		span.SetAttributes(attribute.String("pool.name", pool.Name))
 		defer span.End()
-		return b.Provider.GetPool(pool)
+		return b.Provider.GetPool(ctx, pool)
 	}(ctx)
 	if err != nil {
 		return err
@@ -223,13 +525,16 @@ func (b *BackendController) HandlePool(ctx context.Context, pool *lbv1.Pool, mon
 	// Pool is not empty so update it's data if needed
 	if p != nil {
 		span.SetAttributes(attribute.Bool("pool.exists", true))
+		if err := b.claim("pool", pool.Name, p.Owner, b.appliedPool(pool.Name) != nil); err != nil {
+			return err
+		}
 
 		// Check if pool have members and update the object
 		configuredPool, err := func(ctx context.Context) (*lbv1.Pool, error) {
-			_, span := otel.Tracer(name).Start(ctx, "Provider - GetPoolMembers")
+			ctx, span := otel.Tracer(name).Start(ctx, "Provider - GetPoolMembers")
 			span.SetAttributes(attribute.String("pool.name", p.Name))
 			defer span.End()
-			return b.Provider.GetPoolMembers(p)
+			return b.Provider.GetPoolMembers(ctx, p)
 		}(ctx)
 		if err != nil {
 			return err
@@ -254,21 +559,78 @@ func (b *BackendController) HandlePool(ctx context.Context, pool *lbv1.Pool, mon
 				delMembers = append(delMembers, m)
 			}
 		}
+		// Members missing from or added to the backend outside of the operator are drift
+		appliedPool := b.appliedPool(pool.Name)
+		addMembers, missing := splitMembers(addMembers, func(m lbv1.PoolMember) bool {
+			return appliedPool != nil && ContainsMember(appliedPool.Members, m)
+		})
+		delMembers, unexpected := splitMembers(delMembers, func(m lbv1.PoolMember) bool {
+			return appliedPool != nil && !ContainsMember(appliedPool.Members, m) && b.drainingIndex(pool.Name, m) < 0
+		})
+		for _, m := range missing {
+			b.drift("member %s:%d is missing from pool %s", m.Node.Host, m.Port, pool.Name)
+		}
+		if !b.ObserveOnly {
+			addMembers = append(addMembers, missing...)
+			delMembers = append(delMembers, unexpected...)
+		}
+		// Refuse the removal if it would empty or shrink the pool too much
+		refused := b.RemovalGuard.check(pool.Name, len(configuredPool.Members), len(pool.Members), len(delMembers))
+		if refused != nil {
+			b.log.Info("Pool members removal refused", "name", pool.Name, "nodes", delMembers, "reason", refused.Error())
+			span.SetAttributes(attribute.Bool("pool.members.removal.refused", true))
+			delMembers = nil
+			if r, ok := refused.(*MemberRemovalRefusedError); ok {
+				r.Members = slices.Clone(pool.Members)
+				for _, m := range configuredPool.Members {
+					if !ContainsMember(pool.Members, m) {
+						r.Members = append(r.Members, m)
+					}
+				}
+			}
+		}
+		// The members kept by the guard are not drift
+		if refused == nil || b.ObserveOnly {
+			for _, m := range unexpected {
+				b.drift("member %s:%d was added to pool %s", m.Node.Host, m.Port, pool.Name)
+			}
+		}
 
-		if pool.Monitor != configuredPool.Monitor {
+		monitorDrift := pool.Monitor != configuredPool.Monitor && appliedPool != nil && appliedPool.Monitor == pool.Monitor && !b.writeOnlyMonitor()
+		if monitorDrift {
+			b.drift("pool %s monitor %s differs from the backend", pool.Name, configuredPool.Monitor)
+		}
+		// The pools with write only monitors are always edited to reapply the health checks.
+		// In plan mode they are only planned when the monitor differs from the applied one.
+		backendMonitor := configuredPool.Monitor
+		if b.planning() && b.writeOnlyMonitor() && appliedPool != nil {
+			backendMonitor = appliedPool.Monitor
+		}
+		ownerChanged := pool.Owner != "" && pool.Owner != p.Owner
+		edited := false
+		if (pool.Monitor != backendMonitor && !(monitorDrift && b.ObserveOnly)) || ownerChanged {
 			span.SetAttributes(attribute.String("pool.name", pool.Name), attribute.Bool("pool.update", true))
 			b.log.Info("Pool requires update", "name", pool.Name)
 			b.log.Info("Need", "params", pool)
 			b.log.Info("Have", "params", configuredPool)
 			err := func(ctx context.Context) error {
-				_, span := otel.Tracer(name).Start(ctx, "Provider - EditPool")
+				ctx, span := otel.Tracer(name).Start(ctx, "Provider - EditPool")
 				span.SetAttributes(attribute.String("pool.name", pool.Name))
 				defer span.End()
-				return b.Provider.EditPool(pool)
+				return b.Provider.EditPool(ctx, pool)
 			}(ctx)
 			if err != nil {
 				return err
 			}
+			b.event(corev1.EventTypeNormal, ReasonPoolUpdated, "EditPool", "Updated pool %s", pool.Name)
+			edited = true
+		}
+
+		// Drain the removed members before deleting them. This runs after EditPool since
+		// some providers reset the member state when the pool is edited.
+		delMembers, err = b.drainMembers(ctx, pool, configuredPool, delMembers, edited)
+		if err != nil {
+			return err
 		}
 
 		if addMembers != nil || delMembers != nil {
@@ -281,14 +643,15 @@ func (b *BackendController) HandlePool(ctx context.Context, pool *lbv1.Pool, mon
 				b.log.Info("Add nodes", "nodes", addMembers)
 				for _, m := range addMembers {
 					err := func(ctx context.Context) error {
-						_, span := otel.Tracer(name).Start(ctx, "Provider - CreatePoolMember")
+						ctx, span := otel.Tracer(name).Start(ctx, "Provider - CreatePoolMember")
 						span.SetAttributes(attribute.String("pool.name", pool.Name), attribute.String("pool.member", m.Node.Name))
 						defer span.End()
-						return b.Provider.CreatePoolMember(&m, pool)
+						return b.Provider.CreatePoolMember(ctx, &m, pool)
 					}(ctx)
 					if err != nil {
 						return err
 					}
+					b.event(corev1.EventTypeNormal, ReasonMemberAdded, "CreatePoolMember", "Added member %s (%s:%d) to pool %s", m.Node.Name, m.Node.Host, m.Port, pool.Name)
 				}
 			}
 			// Remove members
@@ -296,46 +659,55 @@ func (b *BackendController) HandlePool(ctx context.Context, pool *lbv1.Pool, mon
 				b.log.Info("Remove nodes", "nodes", delMembers)
 				for _, m := range delMembers {
 					err := func(ctx context.Context) error {
-						_, span := otel.Tracer(name).Start(ctx, "Provider - DeletePoolMember")
+						ctx, span := otel.Tracer(name).Start(ctx, "Provider - DeletePoolMember")
 						span.SetAttributes(attribute.String("pool.name", pool.Name), attribute.String("pool.member", m.Node.Name))
 						defer span.End()
-						return b.Provider.DeletePoolMember(&m, pool)
+						return b.Provider.DeletePoolMember(ctx, &m, pool)
 					}(ctx)
 					if err != nil {
 						return err
 					}
+					b.event(corev1.EventTypeNormal, ReasonMemberRemoved, "DeletePoolMember", "Removed member %s (%s:%d) from pool %s", m.Node.Name, m.Node.Host, m.Port, pool.Name)
 				}
 			}
 
 			b.log.Info("Pool updated successfully", "name", pool.Name)
-			return nil
+			return refused
 		}
 		b.log.Info("Pool does not need update", "name", pool.Name)
 		span.SetAttributes(attribute.String("pool.name", pool.Name), attribute.Bool("pool.members.update", false))
-		return nil
+		return refused
+	}
+
+	if b.appliedPool(pool.Name) != nil {
+		b.drift("pool %s is missing in the backend", pool.Name)
+		if b.ObserveOnly {
+			return nil
+		}
 	}
 
 	// Creating pool
 	b.log.Info("Pool does not exist. Creating...", "name", pool.Name)
 	span.SetAttributes(attribute.Bool("pool.exists", false))
 	err = func(ctx context.Context) error {
-		_, span := otel.Tracer(name).Start(ctx, "Provider - CreatePool")
+		ctx, span := otel.Tracer(name).Start(ctx, "Provider - CreatePool")
 		span.SetAttributes(attribute.String("pool.name", pool.Name))
 		defer span.End()
-		return b.Provider.CreatePool(pool)
+		return b.Provider.CreatePool(ctx, pool)
 	}(ctx)
 	if err != nil {
 		return err
 	}
 	// Adding members to pool
 	b.log.Info("Created pool", "name", pool.Name)
+	b.event(corev1.EventTypeNormal, ReasonPoolCreated, "CreatePool", "Created pool %s with %d members", pool.Name, len(pool.Members))
 	for _, m := range pool.Members {
 		b.log.Info("Adding node to pool", "node", m, "pool", pool)
 		err = func(ctx context.Context) error {
-			_, span := otel.Tracer(name).Start(ctx, "Provider - CreatePoolMember")
+			ctx, span := otel.Tracer(name).Start(ctx, "Provider - CreatePoolMember")
 			span.SetAttributes(attribute.String("pool.name", pool.Name), attribute.String("pool.member", m.Node.Name))
 			defer span.End()
-			return b.Provider.CreatePoolMember(&m, pool)
+			return b.Provider.CreatePoolMember(ctx, &m, pool)
 		}(ctx)
 		if err != nil {
 			return err
@@ -344,6 +716,105 @@ func (b *BackendController) HandlePool(ctx context.Context, pool *lbv1.Pool, mon
 	return nil
 }
 
+// drainMembers disables the members to be removed and returns the ones whose drain period
+// ended and can be deleted. Draining members added back to the pool are enabled again and,
+// when the pool was edited, the ones still draining are disabled again.
+func (b *BackendController) drainMembers(ctx context.Context, pool *lbv1.Pool, configuredPool *lbv1.Pool, delMembers []lbv1.PoolMember, edited bool) ([]lbv1.PoolMember, error) {
+	var draining []lbv1.DrainingMember
+	for _, d := range b.Draining {
+		if d.Pool != pool.Name {
+			draining = append(draining, d)
+			continue
+		}
+		switch {
+		case ContainsMember(pool.Members, d.Member) && ContainsMember(configuredPool.Members, d.Member):
+			b.log.Info("Enabling drained pool member", "pool", pool.Name, "node", d.Member.Node.Name)
+			err := func(ctx context.Context) error {
+				ctx, span := otel.Tracer(name).Start(ctx, "Provider - EditPoolMember")
+				span.SetAttributes(attribute.String("pool.name", pool.Name), attribute.String("pool.member", d.Member.Node.Name), attribute.String("pool.member.status", MemberStatusEnable))
+				defer span.End()
+				return b.Provider.EditPoolMember(ctx, &d.Member, pool, MemberStatusEnable)
+			}(ctx)
+			if err != nil {
+				return nil, err
+			}
+			b.event(corev1.EventTypeNormal, ReasonMemberEnabled, "EditPoolMember", "Enabled drained member %s (%s:%d) in pool %s", d.Member.Node.Name, d.Member.Node.Host, d.Member.Port, pool.Name)
+		case !ContainsMember(pool.Members, d.Member) && ContainsMember(configuredPool.Members, d.Member):
+			// Still draining or its removal was refused by the guard
+			if edited {
+				b.log.Info("Disabling draining pool member after the pool update", "pool", pool.Name, "node", d.Member.Node.Name)
+				err := func(ctx context.Context) error {
+					ctx, span := otel.Tracer(name).Start(ctx, "Provider - EditPoolMember")
+					span.SetAttributes(attribute.String("pool.name", pool.Name), attribute.String("pool.member", d.Member.Node.Name), attribute.String("pool.member.status", MemberStatusDisable))
+					defer span.End()
+					return b.Provider.EditPoolMember(ctx, &d.Member, pool, MemberStatusDisable)
+				}(ctx)
+				if err != nil {
+					return nil, err
+				}
+			}
+			draining = append(draining, d)
+		}
+	}
+	b.Draining = draining
+
+	if b.DrainPeriod == 0 {
+		for _, m := range delMembers {
+			if i := b.drainingIndex(pool.Name, m); i >= 0 {
+				b.Draining = append(b.Draining[:i], b.Draining[i+1:]...)
+			}
+		}
+		return delMembers, nil
+	}
+
+	var drained []lbv1.PoolMember
+	for _, m := range delMembers {
+		i := b.drainingIndex(pool.Name, m)
+		if i < 0 {
+			b.log.Info("Draining pool member", "pool", pool.Name, "node", m.Node.Name, "period", b.DrainPeriod)
+			err := func(ctx context.Context) error {
+				ctx, span := otel.Tracer(name).Start(ctx, "Provider - EditPoolMember")
+				span.SetAttributes(attribute.String("pool.name", pool.Name), attribute.String("pool.member", m.Node.Name), attribute.String("pool.member.status", MemberStatusDisable))
+				defer span.End()
+				return b.Provider.EditPoolMember(ctx, &m, pool, MemberStatusDisable)
+			}(ctx)
+			if err != nil {
+				return nil, err
+			}
+			b.event(corev1.EventTypeNormal, ReasonMemberDisabled, "EditPoolMember", "Draining member %s (%s:%d) in pool %s for %s", m.Node.Name, m.Node.Host, m.Port, pool.Name, b.DrainPeriod)
+			b.Draining = append(b.Draining, lbv1.DrainingMember{Pool: pool.Name, Member: m, Since: metav1.Now()})
+			continue
+		}
+		if time.Since(b.Draining[i].Since.Time) >= b.DrainPeriod {
+			drained = append(drained, m)
+			b.Draining = append(b.Draining[:i], b.Draining[i+1:]...)
+		}
+	}
+	return drained, nil
+}
+
+// drainingIndex returns the index of a pool member in the draining list or -1 if not found
+func (b *BackendController) drainingIndex(pool string, m lbv1.PoolMember) int {
+	for i, d := range b.Draining {
+		if d.Pool == pool && d.Member.Node.Host == m.Node.Host && d.Member.Port == m.Port {
+			return i
+		}
+	}
+	return -1
+}
+
+// NextDrain returns the time until the drain period of the next draining member ends or zero if no members are draining
+func (b *BackendController) NextDrain() time.Duration {
+	var next time.Duration
+	for _, d := range b.Draining {
+		remaining := max(b.DrainPeriod-time.Since(d.Since.Time), time.Second)
+		if next == 0 || remaining < next {
+			next = remaining
+		}
+	}
+	return next
+}
+
 // HandleVIP manages the VIP validation, update and creation
 func (b *BackendController) HandleVIP(ctx context.Context, v *lbv1.VIP) error {
 	var span trace.Span
@@ -353,10 +824,10 @@ func (b *BackendController) HandleVIP(ctx context.Context, v *lbv1.VIP) error {
 
 	// Check if VIP exists
 	vs, err := func(ctx context.Context) (*lbv1.VIP, error) {
-		_, span := otel.Tracer(name).Start(ctx, "Provider - GetVIP")
+		ctx, span := otel.Tracer(name).Start(ctx, "Provider - GetVIP")
 		span.SetAttributes(attribute.String("vip.name", v.Name))
 		defer span.End()
-		return b.Provider.GetVIP(v)
+		return b.Provider.GetVIP(ctx, v)
 	}(ctx)
 
 	// Error getting VIP
@@ -369,23 +840,33 @@ func (b *BackendController) HandleVIP(ctx context.Context, v *lbv1.VIP) error {
 		// Exists, so check to update VIP parameters and pool
 		b.log.Info("VIP exists, check if needs update", "name", vs.Name)
 		span.SetAttributes(attribute.Bool("vip.exists", true))
+		if err := b.claim("VIP", v.Name, vs.Owner, b.appliedVIP(v.Name) != nil); err != nil {
+			return err
+		}
 
-		if v.Port != vs.Port || v.IP != vs.IP || v.Pool != vs.Pool {
+		if v.Port != vs.Port || v.IP != vs.IP || v.Pool != vs.Pool || (v.Owner != "" && v.Owner != vs.Owner) {
+			if applied := b.appliedVIP(v.Name); applied != nil && applied.Port == v.Port && applied.IP == v.IP && applied.Pool == v.Pool && applied.Owner == v.Owner {
+				b.drift("VIP %s configuration differs from the backend", v.Name)
+				if b.ObserveOnly {
+					return nil
+				}
+			}
 			b.log.Info("VIP requires update", "name", v.Name)
 			b.log.Info("Need", "params", v)
 			b.log.Info("Have", "params", vs)
 			span.SetAttributes(attribute.Bool("vip.update", true))
 			err := func(ctx context.Context) error {
-				_, span := otel.Tracer(name).Start(ctx, "Provider - EditVIP")
+				ctx, span := otel.Tracer(name).Start(ctx, "Provider - EditVIP")
 				span.SetAttributes(attribute.String("vip.name", v.Name))
 				defer span.End()
-				return b.Provider.EditVIP(v)
+				return b.Provider.EditVIP(ctx, v)
 			}(ctx)
 
 			if err != nil {
 				return err
 			}
 			b.log.Info("VIP updated successfully", "name", vs.Name)
+			b.event(corev1.EventTypeNormal, ReasonVIPUpdated, "EditVIP", "Updated VIP %s (%s:%d)", v.Name, v.IP, v.Port)
 		} else {
 			b.log.Info("VIP does not need update", "name", vs.Name)
 			span.SetAttributes(attribute.Bool("vip.update", false))
@@ -393,19 +874,27 @@ func (b *BackendController) HandleVIP(ctx context.Context, v *lbv1.VIP) error {
 		return nil
 	}
 
+	if b.appliedVIP(v.Name) != nil {
+		b.drift("VIP %s is missing in the backend", v.Name)
+		if b.ObserveOnly {
+			return nil
+		}
+	}
+
 	// Create VIP
 	b.log.Info("VIP does not exist. Creating...", "name", v.Name)
 	span.SetAttributes(attribute.Bool("vip.exists", false))
 	err = func(ctx context.Context) error {
-		_, span := otel.Tracer(name).Start(ctx, "Provider - CreateVIP")
+		ctx, span := otel.Tracer(name).Start(ctx, "Provider - CreateVIP")
 		span.SetAttributes(attribute.String("vip.name", v.Name))
 		defer span.End()
-		return b.Provider.CreateVIP(v)
+		return b.Provider.CreateVIP(ctx, v)
 	}(ctx)
 	if err != nil {
 		return err
 	}
 	b.log.Info("Created VIP", "name", v.Name, "port", v.Port, "VIP", v.IP, "pool", v.Pool)
+	b.event(corev1.EventTypeNormal, ReasonVIPCreated, "CreateVIP", "Created VIP %s (%s:%d)", v.Name, v.IP, v.Port)
 	return nil
 }
 
@@ -423,14 +912,15 @@ func (b *BackendController) HandleCleanup(ctx context.Context, lb *lbv1.External
 		for _, v := range lb.Status.VIPs {
 			b.log.Info("Cleaning VIP", "VIP", v.Name)
 			err := func(ctx context.Context) error {
-				_, span := otel.Tracer(name).Start(ctx, "Provider - DeleteVIP")
+				ctx, span := otel.Tracer(name).Start(ctx, "Provider - DeleteVIP")
 				span.SetAttributes(attribute.String("lb.name", lb.Name), attribute.String("vip.name", v.Name))
 				defer span.End()
-				return b.Provider.DeleteVIP(&v)
+				return b.Provider.DeleteVIP(ctx, &v)
 			}(ctx)
 			if err != nil {
 				return fmt.Errorf("error in VIP cleanup %s: %v", v.Name, err)
 			}
+			b.event(corev1.EventTypeNormal, ReasonVIPDeleted, "DeleteVIP", "Deleted VIP %s", v.Name)
 		}
 	}
 	// Delete pool members
@@ -439,13 +929,14 @@ func (b *BackendController) HandleCleanup(ctx context.Context, lb *lbv1.External
 			for _, m := range p.Members {
 				b.log.Info("Cleaning pool member", "pool", p.Name, "node", p.Name, "ip", m.Node.Host)
 				err := func(ctx context.Context) error {
-					_, span := otel.Tracer(name).Start(ctx, "Provider - DeletePoolMember")
+					ctx, span := otel.Tracer(name).Start(ctx, "Provider - DeletePoolMember")
 					span.SetAttributes(attribute.String("lb.name", lb.Name), attribute.String("pool.name", p.Name), attribute.String("pool.name", m.Node.Host))
 					defer span.End()
-					return b.Provider.DeletePoolMember(&m, &p)
+					return b.Provider.DeletePoolMember(ctx, &m, &p)
 				}(ctx)
 				if err != nil {
 					b.log.Info("Could not delete pool member", "host", m.Node.Host, "pool", p.Name, "error", err)
+					b.event(corev1.EventTypeWarning, ReasonCleanupFailed, "DeletePoolMember", "Could not delete member %s from pool %s: %v", m.Node.Host, p.Name, err)
 				}
 			}
 		}
@@ -456,14 +947,15 @@ func (b *BackendController) HandleCleanup(ctx context.Context, lb *lbv1.External
 		for _, pool := range lb.Status.Pools {
 			b.log.Info("Cleaning pool", "pool", pool.Name)
 			err := func(ctx context.Context) error {
-				_, span := otel.Tracer(name).Start(ctx, "Provider - DeletePool")
+				ctx, span := otel.Tracer(name).Start(ctx, "Provider - DeletePool")
 				span.SetAttributes(attribute.String("lb.name", lb.Name), attribute.String("pool.name", pool.Name))
 				defer span.End()
-				return b.Provider.DeletePool(&pool)
+				return b.Provider.DeletePool(ctx, &pool)
 			}(ctx)
 			if err != nil {
 				return fmt.Errorf("error in pool cleanup %s: %v", pool.Name, err)
 			}
+			b.event(corev1.EventTypeNormal, ReasonPoolDeleted, "DeletePool", "Deleted pool %s", pool.Name)
 		}
 	}
 
@@ -471,19 +963,185 @@ func (b *BackendController) HandleCleanup(ctx context.Context, lb *lbv1.External
 	b.log.Info("Cleaning Monitor", "Monitor", lb.Status.Monitor)
 	if lb.Status.Monitor != (lbv1.Monitor{}) {
 		err := func(ctx context.Context) error {
-			_, span := otel.Tracer(name).Start(ctx, "Provider - DeleteMonitor")
+			ctx, span := otel.Tracer(name).Start(ctx, "Provider - DeleteMonitor")
 			span.SetAttributes(attribute.String("lb.name", lb.Name), attribute.String("monitor.name", lb.Status.Monitor.Name))
 			defer span.End()
-			return b.Provider.DeleteMonitor(&lb.Status.Monitor)
+			return b.Provider.DeleteMonitor(ctx, &lb.Status.Monitor)
 		}(ctx)
 		if err != nil {
 			return fmt.Errorf("error in monitor cleanup %s: %v", lb.Status.Monitor.Name, err)
 		}
+		b.event(corev1.EventTypeNormal, ReasonMonitorDeleted, "DeleteMonitor", "Deleted monitor %s", lb.Status.Monitor.Name)
+	}
+
+	return nil
+}
+
+// HandleStaleResources removes the VIPs, pools and pool members recorded in a
+// previous status that are no longer part of the desired configuration. This
+// happens when ports are removed from the ExternalLoadBalancer spec.
+func (b *BackendController) HandleStaleResources(ctx context.Context, previous *lbv1.ExternalLoadBalancerStatus, vips []lbv1.VIP, pools []lbv1.Pool) error {
+	var span trace.Span
+	ctx, span = otel.Tracer(name).Start(ctx, "HandleStaleResources")
+	defer span.End()
+
+	// Delete VIPs first since they reference the pools
+	for _, v := range previous.VIPs {
+		if ContainsVIP(vips, v) {
+			continue
+		}
+		b.log.Info("Removing stale VIP", "VIP", v.Name)
+		span.SetAttributes(attribute.Bool("vip.stale", true))
+		err := func(ctx context.Context) error {
+			ctx, span := otel.Tracer(name).Start(ctx, "Provider - DeleteVIP")
+			span.SetAttributes(attribute.String("vip.name", v.Name))
+			defer span.End()
+			return b.Provider.DeleteVIP(ctx, &v)
+		}(ctx)
+		if err != nil {
+			return fmt.Errorf("error removing stale VIP %s: %v", v.Name, err)
+		}
+		b.event(corev1.EventTypeNormal, ReasonVIPDeleted, "DeleteVIP", "Deleted stale VIP %s", v.Name)
+	}
+
+	for _, p := range previous.Pools {
+		if ContainsPool(pools, p) {
+			continue
+		}
+		span.SetAttributes(attribute.Bool("pool.stale", true))
+		// Delete pool members
+		for _, m := range p.Members {
+			b.log.Info("Removing stale pool member", "pool", p.Name, "node", m.Node.Name, "ip", m.Node.Host)
+			err := func(ctx context.Context) error {
+				ctx, span := otel.Tracer(name).Start(ctx, "Provider - DeletePoolMember")
+				span.SetAttributes(attribute.String("pool.name", p.Name), attribute.String("pool.member", m.Node.Name))
+				defer span.End()
+				return b.Provider.DeletePoolMember(ctx, &m, &p)
+			}(ctx)
+			if err != nil {
+				b.log.Info("Could not delete stale pool member", "host", m.Node.Host, "pool", p.Name, "error", err)
+				b.event(corev1.EventTypeWarning, ReasonCleanupFailed, "DeletePoolMember", "Could not delete member %s from stale pool %s: %v", m.Node.Host, p.Name, err)
+			}
+		}
+		// Delete Pool
+		b.log.Info("Removing stale pool", "pool", p.Name)
+		err := func(ctx context.Context) error {
+			ctx, span := otel.Tracer(name).Start(ctx, "Provider - DeletePool")
+			span.SetAttributes(attribute.String("pool.name", p.Name))
+			defer span.End()
+			return b.Provider.DeletePool(ctx, &p)
+		}(ctx)
+		if err != nil {
+			return fmt.Errorf("error removing stale pool %s: %v", p.Name, err)
+		}
+		b.event(corev1.EventTypeNormal, ReasonPoolDeleted, "DeletePool", "Deleted stale pool %s", p.Name)
+	}
+	return nil
+}
+
+// HandleStaleMonitor removes the monitor recorded in a previous status when it was renamed
+// by the naming template. It must run after HandleStaleResources removed the pools using it.
+func (b *BackendController) HandleStaleMonitor(ctx context.Context, previous *lbv1.ExternalLoadBalancerStatus, monitor *lbv1.Monitor) error {
+	if previous.Monitor.Name == "" || previous.Monitor.Name == monitor.Name {
+		return nil
 	}
+	var span trace.Span
+	ctx, span = otel.Tracer(name).Start(ctx, "HandleStaleMonitor")
+	span.SetAttributes(attribute.String("monitor.name", previous.Monitor.Name))
+	defer span.End()
 
+	b.log.Info("Removing stale monitor", "monitor", previous.Monitor.Name)
+	err := func(ctx context.Context) error {
+		ctx, span := otel.Tracer(name).Start(ctx, "Provider - DeleteMonitor")
+		span.SetAttributes(attribute.String("monitor.name", previous.Monitor.Name))
+		defer span.End()
+		return b.Provider.DeleteMonitor(ctx, &previous.Monitor)
+	}(ctx)
+	if err != nil {
+		return fmt.Errorf("error removing stale monitor %s: %v", previous.Monitor.Name, err)
+	}
+	b.event(corev1.EventTypeNormal, ReasonMonitorDeleted, "DeleteMonitor", "Deleted stale monitor %s", previous.Monitor.Name)
 	return nil
 }
 
+// drift records a difference between the backend and the applied configuration
+func (b *BackendController) drift(format string, args ...interface{}) {
+	d := fmt.Sprintf(format, args...)
+	b.log.Info("Drift detected", "drift", d, "observeOnly", b.ObserveOnly)
+	b.Drift = append(b.Drift, d)
+	b.event(corev1.EventTypeWarning, ReasonDriftDetected, "Resync", "%s", d)
+}
+
+// claim checks if the existing backend object tagged with owner can be modified by the load
+// balancer. Objects in the applied configuration are owned, the others are adopted if tagged
+// with the Owner or if Adopt is set and refused with a NotOwnedError otherwise.
+func (b *BackendController) claim(kind string, name string, owner string, applied bool) error {
+	if applied {
+		return nil
+	}
+	if b.Owner != "" && owner != b.Owner && !b.Adopt {
+		b.log.Info("Refusing to modify backend object not owned by the load balancer", "kind", kind, "name", name, "owner", owner)
+		return &NotOwnedError{Kind: kind, Name: name, Owner: owner}
+	}
+	b.adopted(kind, name)
+	return nil
+}
+
+// adopted records an existing backend object not in the applied configuration, like the
+// objects retained by a deleted ExternalLoadBalancer, being taken over by the load balancer
+func (b *BackendController) adopted(kind string, name string) {
+	b.log.Info("Adopting existing backend object", "kind", kind, "name", name)
+	b.event(corev1.EventTypeNormal, ReasonAdopted, "Adopt", "Adopted existing %s %s", kind, name)
+}
+
+// splitMembers splits the members in the ones not matching and the ones matching isDrift
+func splitMembers(members []lbv1.PoolMember, isDrift func(lbv1.PoolMember) bool) ([]lbv1.PoolMember, []lbv1.PoolMember) {
+	var changes, drifted []lbv1.PoolMember
+	for _, m := range members {
+		if isDrift(m) {
+			drifted = append(drifted, m)
+		} else {
+			changes = append(changes, m)
+		}
+	}
+	return changes, drifted
+}
+
+// appliedPool returns the pool from the applied configuration or nil if not found
+func (b *BackendController) appliedPool(name string) *lbv1.Pool {
+	if b.Applied == nil {
+		return nil
+	}
+	for i, p := range b.Applied.Pools {
+		if p.Name == name {
+			return &b.Applied.Pools[i]
+		}
+	}
+	return nil
+}
+
+// appliedVIP returns the VIP from the applied configuration or nil if not found
+func (b *BackendController) appliedVIP(name string) *lbv1.VIP {
+	if b.Applied == nil {
+		return nil
+	}
+	for i, v := range b.Applied.VIPs {
+		if v.Name == name {
+			return &b.Applied.VIPs[i]
+		}
+	}
+	return nil
+}
+
+// event emits an event regarding the Regarding object if a Recorder is set
+func (b *BackendController) event(eventtype, reason, action, note string, args ...interface{}) {
+	// Only warnings are emitted in plan mode since the changes are not applied
+	if b.Recorder == nil || b.Regarding == nil || (b.planning() && eventtype == corev1.EventTypeNormal) {
+		return
+	}
+	b.Recorder.Eventf(b.Regarding, nil, eventtype, reason, action, note, args...)
+}
+
 func ContainsMember(arr []lbv1.PoolMember, m lbv1.PoolMember) bool {
 	for _, a := range arr {
 		if a.Node.Host == m.Node.Host && a.Port == m.Port {
@@ -492,3 +1150,31 @@ func ContainsMember(arr []lbv1.PoolMember, m lbv1.PoolMember) bool {
 	}
 	return false
 }
+
+func ContainsVIP(arr []lbv1.VIP, v lbv1.VIP) bool {
+	for _, a := range arr {
+		if a.Name == v.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func ContainsPool(arr []lbv1.Pool, p lbv1.Pool) bool {
+	for _, a := range arr {
+		if a.Name == p.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsIPv6 returns true if the address is an IPv6 address.
+// Zones and F5 route domains in the "%<id>" notation are ignored.
+func IsIPv6(address string) bool {
+	if i := strings.IndexByte(address, '%'); i >= 0 {
+		address = address[:i]
+	}
+	ip := net.ParseIP(address)
+	return ip != nil && ip.To4() == nil
+}
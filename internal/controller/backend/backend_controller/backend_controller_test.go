@@ -26,12 +26,18 @@ package controller_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"reflect"
+	"regexp"
+	"sync"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
 
 	lbv1 "github.com/carlosedp/lbconfig-operator/api/v1"
 	. "github.com/carlosedp/lbconfig-operator/internal/controller/backend/backend_controller"
@@ -44,6 +50,202 @@ func TestBackendController(t *testing.T) {
 	RunSpecs(t, "Backend Controller Suite")
 }
 
+// hostCheckProvider wraps the dummy provider recording the host it was created for
+type hostCheckProvider struct {
+	d.DummyProvider
+	host string
+}
+
+func (p *hostCheckProvider) Create(ctx context.Context, lbBackend lbv1.Provider, username string, password string) error {
+	p.host = lbBackend.Host
+	return p.DummyProvider.Create(ctx, lbBackend, username, password)
+}
+
+// GetMonitor fails if the reconcile context was cancelled
+func (p *hostCheckProvider) GetMonitor(ctx context.Context, m *lbv1.Monitor) (*lbv1.Monitor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.DummyProvider.GetMonitor(ctx, m)
+}
+
+// monitorProvider wraps the dummy provider keeping the configured monitor and counting the edits
+type monitorProvider struct {
+	d.DummyProvider
+	configured *lbv1.Monitor
+	edits      int
+	deleted    []string
+}
+
+func (p *monitorProvider) GetMonitor(ctx context.Context, m *lbv1.Monitor) (*lbv1.Monitor, error) {
+	return p.configured, nil
+}
+
+func (p *monitorProvider) EditMonitor(ctx context.Context, m *lbv1.Monitor) error {
+	p.edits++
+	configured := *m
+	p.configured = &configured
+	return nil
+}
+
+func (p *monitorProvider) DeleteMonitor(ctx context.Context, m *lbv1.Monitor) error {
+	p.deleted = append(p.deleted, m.Name)
+	return nil
+}
+
+// poolProvider wraps the dummy provider keeping the configured pool and counting the member changes
+type poolProvider struct {
+	d.DummyProvider
+	configured *lbv1.Pool
+	added      int
+	removed    int
+	edits      []string
+}
+
+func (p *poolProvider) GetPool(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
+	return p.configured, nil
+}
+
+func (p *poolProvider) GetPoolMembers(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
+	return p.configured, nil
+}
+
+// EditPool enables all the pool members like the providers replacing the pool servers
+func (p *poolProvider) EditPool(ctx context.Context, pool *lbv1.Pool) error {
+	if p.configured != nil {
+		for range p.configured.Members {
+			p.edits = append(p.edits, MemberStatusEnable)
+		}
+	}
+	return nil
+}
+
+func (p *poolProvider) CreatePoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
+	p.added++
+	return nil
+}
+
+func (p *poolProvider) EditPoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool, status string) error {
+	p.edits = append(p.edits, status)
+	return nil
+}
+
+func (p *poolProvider) DeletePoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
+	p.removed++
+	return nil
+}
+
+// writeOnlyProvider wraps the pool provider with monitors that can't be read back like HAProxy
+type writeOnlyProvider struct {
+	poolProvider
+	monitor *lbv1.Monitor
+}
+
+func (p *writeOnlyProvider) WriteOnlyMonitor() {}
+
+func (p *writeOnlyProvider) GetMonitor(ctx context.Context, m *lbv1.Monitor) (*lbv1.Monitor, error) {
+	return &lbv1.Monitor{}, nil
+}
+
+func (p *writeOnlyProvider) EditMonitor(ctx context.Context, m *lbv1.Monitor) error {
+	p.monitor = m
+	return nil
+}
+
+// untaggedProvider wraps the monitor provider with monitors that can't be tagged like Citrix ADC
+type untaggedProvider struct {
+	monitorProvider
+}
+
+func (p *untaggedProvider) UntaggedMonitor() {}
+
+func (p *untaggedProvider) EditMonitor(ctx context.Context, m *lbv1.Monitor) error {
+	p.edits++
+	configured := *m
+	configured.Owner = ""
+	p.configured = &configured
+	return nil
+}
+
+// legacyProvider implements the context-less Provider interface recording the called methods
+type legacyProvider struct {
+	calls []string
+}
+
+func (p *legacyProvider) Create(ctx context.Context, lbBackend lbv1.Provider, username string, password string) error {
+	p.calls = append(p.calls, "Create")
+	return nil
+}
+func (p *legacyProvider) Version(ctx context.Context) (string, error) { return "legacy", nil }
+func (p *legacyProvider) Connect() error                              { p.calls = append(p.calls, "Connect"); return nil }
+func (p *legacyProvider) Close() error                                { p.calls = append(p.calls, "Close"); return nil }
+func (p *legacyProvider) GetMonitor(m *lbv1.Monitor) (*lbv1.Monitor, error) {
+	p.calls = append(p.calls, "GetMonitor")
+	return nil, nil
+}
+func (p *legacyProvider) CreateMonitor(m *lbv1.Monitor) error {
+	p.calls = append(p.calls, "CreateMonitor")
+	return nil
+}
+func (p *legacyProvider) EditMonitor(m *lbv1.Monitor) error   { return nil }
+func (p *legacyProvider) DeleteMonitor(m *lbv1.Monitor) error { return nil }
+func (p *legacyProvider) GetPool(pool *lbv1.Pool) (*lbv1.Pool, error) {
+	p.calls = append(p.calls, "GetPool")
+	return nil, nil
+}
+func (p *legacyProvider) CreatePool(pool *lbv1.Pool) error {
+	p.calls = append(p.calls, "CreatePool")
+	return nil
+}
+func (p *legacyProvider) EditPool(pool *lbv1.Pool) error                     { return nil }
+func (p *legacyProvider) DeletePool(pool *lbv1.Pool) error                   { return nil }
+func (p *legacyProvider) GetPoolMembers(pool *lbv1.Pool) (*lbv1.Pool, error) { return pool, nil }
+func (p *legacyProvider) CreatePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool) error {
+	p.calls = append(p.calls, "CreatePoolMember")
+	return nil
+}
+func (p *legacyProvider) EditPoolMember(m *lbv1.PoolMember, pool *lbv1.Pool, status string) error {
+	return nil
+}
+func (p *legacyProvider) DeletePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool) error { return nil }
+func (p *legacyProvider) GetVIP(v *lbv1.VIP) (*lbv1.VIP, error) {
+	p.calls = append(p.calls, "GetVIP")
+	return nil, nil
+}
+func (p *legacyProvider) CreateVIP(v *lbv1.VIP) error {
+	p.calls = append(p.calls, "CreateVIP")
+	return nil
+}
+func (p *legacyProvider) EditVIP(v *lbv1.VIP) error   { return nil }
+func (p *legacyProvider) DeleteVIP(v *lbv1.VIP) error { return nil }
+
+func init() {
+	err := RegisterProvider("Legacy", func() Provider { return new(legacyProvider) })
+	if err != nil {
+		panic(err)
+	}
+	err = RegisterProviderV2("HostCheck", func() ProviderV2 { return new(hostCheckProvider) })
+	if err != nil {
+		panic(err)
+	}
+	err = RegisterProviderV2("MonitorDrift", func() ProviderV2 { return new(monitorProvider) })
+	if err != nil {
+		panic(err)
+	}
+	err = RegisterProviderV2("PoolGuard", func() ProviderV2 { return new(poolProvider) })
+	if err != nil {
+		panic(err)
+	}
+	err = RegisterProviderV2("WriteOnly", func() ProviderV2 { return new(writeOnlyProvider) })
+	if err != nil {
+		panic(err)
+	}
+	err = RegisterProviderV2("Untagged", func() ProviderV2 { return new(untaggedProvider) })
+	if err != nil {
+		panic(err)
+	}
+}
+
 const (
 	dummyHostIP = "1.2.3.4"
 	testNodeIP  = "1.1.1.1"
@@ -106,7 +308,7 @@ var _ = Describe("Controllers/Backend/controller/backend_controller", func() {
 		})
 
 		It("Should return error if backend provider tries to register again", func() {
-			err := RegisterProvider("Dummy", new(d.DummyProvider))
+			err := RegisterProviderV2("Dummy", func() ProviderV2 { return new(d.DummyProvider) })
 			Expect(err).To(MatchError(MatchRegexp("provider already exists.*")))
 		})
 
@@ -138,6 +340,104 @@ var _ = Describe("Controllers/Backend/controller/backend_controller", func() {
 			Expect(reflect.TypeOf(createdBackend.Provider)).Should(Equal(reflect.TypeOf(&d.DummyProvider{})))
 		})
 
+		It("Should create an isolated provider instance for each backend", func() {
+			first, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			second, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(first.Provider).ShouldNot(BeIdenticalTo(second.Provider))
+		})
+
+		It("Should not share provider state between concurrent backends", func() {
+			const workers = 10
+			var wg sync.WaitGroup
+			errs := make(chan error, workers)
+			for i := range workers {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					host := fmt.Sprintf("10.0.0.%d", i+1)
+					lbProvider := lbv1.Provider{
+						Vendor: "HostCheck",
+						Host:   host,
+						Port:   443,
+						Creds:  "secretname",
+					}
+					b, err := CreateBackend(ctx, &lbProvider, fmt.Sprintf("user-%d", i), "password")
+					if err != nil {
+						errs <- err
+						return
+					}
+					if err := b.HandleMonitors(ctx, &monitor); err != nil {
+						errs <- err
+						return
+					}
+					if err := b.HandlePool(ctx, pool, &monitor); err != nil {
+						errs <- err
+						return
+					}
+					if err := b.HandleVIP(ctx, VIP); err != nil {
+						errs <- err
+						return
+					}
+					if got := b.Provider.(*hostCheckProvider).host; got != host {
+						errs <- fmt.Errorf("provider host changed from %s to %s", host, got)
+					}
+				}(i)
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("Should adapt legacy providers to the context-aware interface", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "Legacy"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(createdBackend.Provider.Connect(ctx)).To(Succeed())
+			Expect(createdBackend.HandleMonitors(ctx, &monitor)).To(Succeed())
+			Expect(createdBackend.HandlePool(ctx, pool, &monitor)).To(Succeed())
+			Expect(createdBackend.HandleVIP(ctx, VIP)).To(Succeed())
+			Expect(createdBackend.Provider.Close(ctx)).To(Succeed())
+
+			adapted, ok := createdBackend.Provider.(interface{ Unwrap() Provider })
+			Expect(ok).To(BeTrue())
+			legacy := adapted.Unwrap().(*legacyProvider)
+			Expect(legacy.calls).To(Equal([]string{
+				"Create", "Connect", "GetMonitor", "CreateMonitor", "GetPool", "CreatePool",
+				"CreatePoolMember", "CreatePoolMember", "GetVIP", "CreateVIP", "Close",
+			}))
+		})
+
+		It("Should keep the legacy provider capabilities and honor the context", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "Legacy"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			createdBackend.Plan()
+			Expect(createdBackend.Version(ctx)).To(Equal("legacy"))
+
+			cancelled, cancel := context.WithCancel(ctx)
+			cancel()
+			Expect(createdBackend.HandleMonitors(cancelled, &monitor)).To(MatchError(ContainSubstring(context.Canceled.Error())))
+			legacy := createdBackend.Provider.(interface{ Unwrap() ProviderV2 }).Unwrap().(interface{ Unwrap() Provider }).Unwrap().(*legacyProvider)
+			Expect(legacy.calls).To(Equal([]string{"Create"}))
+		})
+
+		It("Should pass the reconcile context to the provider", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "HostCheck"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			cancelCtx, cancel := context.WithCancel(ctx)
+			cancel()
+			err = createdBackend.HandleMonitors(cancelCtx, &monitor)
+			Expect(err).To(MatchError(ContainSubstring(context.Canceled.Error())))
+		})
+
 		It("Should handle a provider monitor", func() {
 			createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 			Expect(err).ShouldNot(HaveOccurred())
@@ -145,6 +445,89 @@ var _ = Describe("Controllers/Backend/controller/backend_controller", func() {
 			Expect(err).ShouldNot(HaveOccurred())
 		})
 
+		It("Should update a monitor when any of its fields drift", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "MonitorDrift"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			provider := createdBackend.Provider.(*monitorProvider)
+
+			want := monitor.WithDefaults()
+			want.ExpectedStatus = 200
+			configured := want
+			provider.configured = &configured
+			Expect(createdBackend.HandleMonitors(ctx, &want)).To(Succeed())
+			Expect(provider.edits).To(Equal(0))
+
+			want.Host = "app.example.com"
+			Expect(createdBackend.HandleMonitors(ctx, &want)).To(Succeed())
+			Expect(provider.edits).To(Equal(1))
+			Expect(provider.configured.Host).To(Equal("app.example.com"))
+
+			want.Fall = 3
+			Expect(createdBackend.HandleMonitors(ctx, &want)).To(Succeed())
+			Expect(provider.edits).To(Equal(2))
+		})
+
+		It("Should refuse to modify monitors not owned by the load balancer", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "MonitorDrift"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			provider := createdBackend.Provider.(*monitorProvider)
+			createdBackend.Owner = OwnerTag("cluster", "default", "dummy-backend")
+
+			want := monitor.WithDefaults()
+			want.Name = "test-monitor"
+			want.Owner = createdBackend.Owner
+			configured := want
+			configured.Owner = ""
+			provider.configured = &configured
+
+			// Monitors without the owner tag require adopt
+			var notOwned *NotOwnedError
+			Expect(errors.As(createdBackend.HandleMonitors(ctx, &want), &notOwned)).To(BeTrue())
+			Expect(notOwned.Kind).To(Equal("monitor"))
+			Expect(provider.edits).To(Equal(0))
+
+			By("Tagging the monitor with the owner when adopting")
+			createdBackend.Adopt = true
+			Expect(createdBackend.HandleMonitors(ctx, &want)).To(Succeed())
+			Expect(provider.edits).To(Equal(1))
+			Expect(provider.configured.Owner).To(Equal(createdBackend.Owner))
+
+			By("Modifying the monitors tagged with the owner")
+			createdBackend.Adopt = false
+			Expect(createdBackend.HandleMonitors(ctx, &want)).To(Succeed())
+			Expect(provider.edits).To(Equal(1))
+		})
+
+		It("Should adopt the untagged monitors of providers that can't tag them", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "Untagged"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			provider := createdBackend.Provider.(*untaggedProvider)
+			createdBackend.Owner = OwnerTag("cluster", "default", "dummy-backend")
+
+			// The monitor retained by a deleted instance is recreated without adopt
+			want := monitor.WithDefaults()
+			want.Name = "test-monitor"
+			want.Owner = createdBackend.Owner
+			configured := want
+			configured.Owner = ""
+			provider.configured = &configured
+			Expect(createdBackend.HandleMonitors(ctx, &want)).To(Succeed())
+			Expect(provider.edits).To(Equal(1))
+
+			By("Refusing the monitors tagged with another owner")
+			configured.Owner = OwnerTag("cluster", "default", "other")
+			provider.configured = &configured
+			var notOwned *NotOwnedError
+			Expect(errors.As(createdBackend.HandleMonitors(ctx, &want), &notOwned)).To(BeTrue())
+			Expect(provider.edits).To(Equal(1))
+		})
+
 		It("Should handle a provider pool", func() {
 			createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 			Expect(err).ShouldNot(HaveOccurred())
@@ -152,6 +535,291 @@ var _ = Describe("Controllers/Backend/controller/backend_controller", func() {
 			Expect(err).ShouldNot(HaveOccurred())
 		})
 
+		It("Should refuse pool member removals not allowed by the removal guard", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "PoolGuard"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			provider := createdBackend.Provider.(*poolProvider)
+			provider.configured = pool.DeepCopy()
+
+			// An empty node list must not empty the pool
+			emptyPool := pool.DeepCopy()
+			emptyPool.Members = nil
+			createdBackend.RemovalGuard = RemovalGuard{MinMembers: 1}
+			err = createdBackend.HandlePool(ctx, emptyPool, &monitor)
+			var refused *MemberRemovalRefusedError
+			Expect(errors.As(err, &refused)).To(BeTrue())
+			Expect(refused.Removed).To(Equal(2))
+			Expect(refused.Members).To(Equal(pool.Members))
+			Expect(provider.removed).To(Equal(0))
+
+			// Removing one of two members is allowed up to 50%, new members are still added
+			scaledPool := pool.DeepCopy()
+			scaledPool.Members = []lbv1.PoolMember{pool.Members[0], {Node: lbv1.Node{Name: "test-node-3", Host: "1.1.1.3"}, Port: 80}}
+			createdBackend.RemovalGuard = RemovalGuard{MaxRemovalPercent: 50}
+			Expect(createdBackend.HandlePool(ctx, scaledPool, &monitor)).To(Succeed())
+			Expect(provider.added).To(Equal(1))
+			Expect(provider.removed).To(Equal(1))
+
+			err = createdBackend.HandlePool(ctx, emptyPool, &monitor)
+			Expect(errors.As(err, &refused)).To(BeTrue())
+			Expect(provider.removed).To(Equal(1))
+
+			// Without a guard all members are removed
+			createdBackend.RemovalGuard = RemovalGuard{}
+			Expect(createdBackend.HandlePool(ctx, emptyPool, &monitor)).To(Succeed())
+			Expect(provider.removed).To(Equal(3))
+		})
+
+		It("Should drain pool members before removing them", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "PoolGuard"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			provider := createdBackend.Provider.(*poolProvider)
+			provider.configured = pool.DeepCopy()
+			createdBackend.DrainPeriod = time.Minute
+
+			// The removed member is disabled and kept in the pool
+			scaledPool := pool.DeepCopy()
+			scaledPool.Members = pool.Members[:1]
+			Expect(createdBackend.HandlePool(ctx, scaledPool, &monitor)).To(Succeed())
+			Expect(provider.edits).To(Equal([]string{MemberStatusDisable}))
+			Expect(provider.removed).To(Equal(0))
+			Expect(createdBackend.Draining).To(HaveLen(1))
+			Expect(createdBackend.Draining[0].Member.Node.Name).To(Equal("test-node-2"))
+			Expect(createdBackend.NextDrain()).To(BeNumerically(">", 50*time.Second))
+
+			// Still draining
+			Expect(createdBackend.HandlePool(ctx, scaledPool, &monitor)).To(Succeed())
+			Expect(provider.edits).To(HaveLen(1))
+			Expect(provider.removed).To(Equal(0))
+
+			// Removed once the drain period ends
+			createdBackend.Draining[0].Since = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+			Expect(createdBackend.HandlePool(ctx, scaledPool, &monitor)).To(Succeed())
+			Expect(provider.removed).To(Equal(1))
+			Expect(createdBackend.Draining).To(BeEmpty())
+			Expect(createdBackend.NextDrain()).To(BeZero())
+
+			// A draining member added back to the pool is enabled again
+			Expect(createdBackend.HandlePool(ctx, scaledPool, &monitor)).To(Succeed())
+			Expect(createdBackend.Draining).To(HaveLen(1))
+			Expect(createdBackend.HandlePool(ctx, pool, &monitor)).To(Succeed())
+			Expect(provider.edits).To(Equal([]string{MemberStatusDisable, MemberStatusDisable, MemberStatusEnable}))
+			Expect(createdBackend.Draining).To(BeEmpty())
+		})
+
+		It("Should keep draining members disabled when the pool is edited", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "PoolGuard"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			provider := createdBackend.Provider.(*poolProvider)
+			provider.configured = pool.DeepCopy()
+			createdBackend.DrainPeriod = time.Minute
+
+			scaledPool := pool.DeepCopy()
+			scaledPool.Members = pool.Members[:1]
+			Expect(createdBackend.HandlePool(ctx, scaledPool, &monitor)).To(Succeed())
+			Expect(provider.edits).To(Equal([]string{MemberStatusDisable}))
+
+			// The pool edit enables all members so the draining one is disabled again
+			scaledPool.Monitor = "new-monitor"
+			Expect(createdBackend.HandlePool(ctx, scaledPool, &monitor)).To(Succeed())
+			Expect(provider.edits).To(Equal([]string{MemberStatusDisable, MemberStatusEnable, MemberStatusEnable, MemberStatusDisable}))
+			Expect(createdBackend.Draining).To(HaveLen(1))
+			Expect(provider.removed).To(Equal(0))
+		})
+
+		It("Should emit events for the backend changes", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "PoolGuard"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			provider := createdBackend.Provider.(*poolProvider)
+			provider.configured = pool.DeepCopy()
+			recorder := events.NewFakeRecorder(10)
+			createdBackend.Recorder = recorder
+			createdBackend.Regarding = loadBalancer
+			createdBackend.Applied = &lbv1.ExternalLoadBalancerStatus{Pools: []lbv1.Pool{*pool}}
+
+			scaledPool := pool.DeepCopy()
+			scaledPool.Members = []lbv1.PoolMember{pool.Members[0], {Node: lbv1.Node{Name: "test-node-3", Host: "1.1.1.3"}, Port: 80}}
+			Expect(createdBackend.HandlePool(ctx, scaledPool, &monitor)).To(Succeed())
+			Expect(recorder.Events).To(Receive(Equal("Normal " + ReasonMemberAdded + " Added member test-node-3 (1.1.1.3:80) to pool " + pool.Name)))
+			Expect(recorder.Events).To(Receive(Equal("Normal " + ReasonMemberRemoved + " Removed member test-node-2 (1.1.1.2:80) from pool " + pool.Name)))
+			Expect(recorder.Events).NotTo(Receive())
+
+			// No events without changes
+			provider.configured = scaledPool.DeepCopy()
+			Expect(createdBackend.HandlePool(ctx, scaledPool, &monitor)).To(Succeed())
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("Should adopt existing backend objects not in the applied configuration", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "PoolGuard"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			provider := createdBackend.Provider.(*poolProvider)
+			provider.configured = pool.DeepCopy()
+			recorder := events.NewFakeRecorder(10)
+			createdBackend.Recorder = recorder
+			createdBackend.Regarding = loadBalancer
+
+			Expect(createdBackend.HandlePool(ctx, pool, &monitor)).To(Succeed())
+			Expect(recorder.Events).To(Receive(Equal("Normal " + ReasonAdopted + " Adopted existing pool " + pool.Name)))
+			Expect(recorder.Events).NotTo(Receive())
+			Expect(provider.added).To(Equal(0))
+			Expect(provider.removed).To(Equal(0))
+		})
+
+		It("Should refuse to modify pools not owned by the load balancer", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "PoolGuard"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			provider := createdBackend.Provider.(*poolProvider)
+			provider.configured = pool.DeepCopy()
+			provider.configured.Owner = OwnerTag("other-cluster", "default", "dummy-backend")
+			createdBackend.Owner = OwnerTag("cluster", "default", "dummy-backend")
+			owned := pool.DeepCopy()
+			owned.Owner = createdBackend.Owner
+			owned.Members = pool.Members[:1]
+
+			var notOwned *NotOwnedError
+			Expect(errors.As(createdBackend.HandlePool(ctx, owned, &monitor), &notOwned)).To(BeTrue())
+			Expect(notOwned.Owner).To(Equal(provider.configured.Owner))
+			Expect(provider.removed).To(Equal(0))
+
+			By("Taking over the pool when adopting")
+			createdBackend.Adopt = true
+			Expect(createdBackend.HandlePool(ctx, owned, &monitor)).To(Succeed())
+			Expect(provider.removed).To(Equal(1))
+
+			By("Modifying the pools in the applied configuration")
+			createdBackend.Adopt = false
+			createdBackend.Applied = &lbv1.ExternalLoadBalancerStatus{Pools: []lbv1.Pool{*pool}}
+			Expect(createdBackend.HandlePool(ctx, owned, &monitor)).To(Succeed())
+		})
+
+		It("Should detect and correct drift from the applied configuration", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "PoolGuard"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			provider := createdBackend.Provider.(*poolProvider)
+			createdBackend.Applied = &lbv1.ExternalLoadBalancerStatus{Pools: []lbv1.Pool{*pool}, VIPs: []lbv1.VIP{*VIP}}
+
+			// A member was removed and another added outside of the operator
+			drifted := pool.DeepCopy()
+			drifted.Members = []lbv1.PoolMember{pool.Members[0], {Node: lbv1.Node{Name: "test-node-3", Host: "1.1.1.3"}, Port: 80}}
+			provider.configured = drifted
+
+			By("Only reporting the drift in observe only mode")
+			createdBackend.ObserveOnly = true
+			Expect(createdBackend.HandlePool(ctx, pool, &monitor)).To(Succeed())
+			Expect(createdBackend.Drift).To(ConsistOf(
+				"member 1.1.1.2:80 is missing from pool test-pool",
+				"member 1.1.1.3:80 was added to pool test-pool"))
+			Expect(provider.added).To(Equal(0))
+			Expect(provider.removed).To(Equal(0))
+
+			By("Correcting the drift")
+			createdBackend.ObserveOnly = false
+			createdBackend.Drift = nil
+			Expect(createdBackend.HandlePool(ctx, pool, &monitor)).To(Succeed())
+			Expect(createdBackend.Drift).To(HaveLen(2))
+			Expect(provider.added).To(Equal(1))
+			Expect(provider.removed).To(Equal(1))
+
+			By("Not reporting changes to the requested configuration as drift")
+			createdBackend.Drift = nil
+			provider.configured = pool.DeepCopy()
+			scaledPool := pool.DeepCopy()
+			scaledPool.Members = pool.Members[:1]
+			Expect(createdBackend.HandlePool(ctx, scaledPool, &monitor)).To(Succeed())
+			Expect(createdBackend.Drift).To(BeEmpty())
+			Expect(provider.removed).To(Equal(2))
+
+			By("Reporting missing pools and VIPs")
+			provider.configured = nil
+			createdBackend.ObserveOnly = true
+			Expect(createdBackend.HandlePool(ctx, pool, &monitor)).To(Succeed())
+			Expect(createdBackend.HandleVIP(ctx, VIP)).To(Succeed())
+			Expect(createdBackend.Drift).To(ConsistOf("pool test-pool is missing in the backend", "VIP test-vip is missing in the backend"))
+			Expect(provider.added).To(Equal(1))
+		})
+
+		It("Should not report drift for monitors that can't be read back", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "WriteOnly"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			provider := createdBackend.Provider.(*writeOnlyProvider)
+			m := monitor
+			m.Name = "test-monitor"
+			monitoredPool := pool.DeepCopy()
+			monitoredPool.Monitor = m.Name
+			provider.configured = monitoredPool.DeepCopy()
+			provider.configured.Monitor = "changed"
+			createdBackend.Applied = &lbv1.ExternalLoadBalancerStatus{Monitor: m, Pools: []lbv1.Pool{*monitoredPool}}
+			createdBackend.ObserveOnly = true
+
+			Expect(createdBackend.HandleMonitors(ctx, &m)).To(Succeed())
+			Expect(createdBackend.HandlePool(ctx, monitoredPool, &m)).To(Succeed())
+			Expect(createdBackend.Drift).To(BeEmpty())
+			// The monitor is still applied so the new members get its health checks
+			Expect(provider.monitor).To(Equal(&m))
+		})
+
+		It("Should not plan changes for applied monitors that can't be read back", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "WriteOnly"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			provider := createdBackend.Provider.(*writeOnlyProvider)
+			m := monitor
+			m.Name = "test-monitor"
+			monitoredPool := pool.DeepCopy()
+			monitoredPool.Monitor = m.Name
+			provider.configured = monitoredPool.DeepCopy()
+			provider.configured.Monitor = "changed"
+			createdBackend.Applied = &lbv1.ExternalLoadBalancerStatus{Monitor: m, Pools: []lbv1.Pool{*monitoredPool}}
+
+			createdBackend.Plan()
+			Expect(createdBackend.HandleMonitors(ctx, &m)).To(Succeed())
+			Expect(createdBackend.HandlePool(ctx, monitoredPool, &m)).To(Succeed())
+			Expect(createdBackend.PlannedChanges()).To(BeEmpty())
+
+			m.Interval = 30
+			Expect(createdBackend.HandleMonitors(ctx, &m)).To(Succeed())
+			Expect(createdBackend.PlannedChanges()).To(ConsistOf("edit monitor test-monitor"))
+			Expect(provider.monitor).To(BeNil())
+		})
+
+		It("Should only record the changes in plan mode", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "PoolGuard"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			provider := createdBackend.Provider.(*poolProvider)
+			current := pool.DeepCopy()
+			current.Members = []lbv1.PoolMember{pool.Members[0], {Node: lbv1.Node{Name: "test-node-3", Host: "1.1.1.3"}, Port: 80}}
+			provider.configured = current
+
+			createdBackend.Plan()
+			Expect(createdBackend.HandlePool(ctx, pool, &monitor)).To(Succeed())
+			Expect(createdBackend.PlannedChanges()).To(ConsistOf(
+				"add member 1.1.1.2:80 to pool test-pool",
+				"remove member 1.1.1.3:80 from pool test-pool"))
+			Expect(provider.added).To(Equal(0))
+			Expect(provider.removed).To(Equal(0))
+			Expect(createdBackend.Provider.Close(ctx)).To(Succeed())
+		})
+
 		It("Should handle a provider VIP", func() {
 			createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 			Expect(err).ShouldNot(HaveOccurred())
@@ -159,6 +827,40 @@ var _ = Describe("Controllers/Backend/controller/backend_controller", func() {
 			Expect(err).ShouldNot(HaveOccurred())
 		})
 
+		It("Should handle removal of stale provider resources", func() {
+			createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			stalePool := pool.DeepCopy()
+			stalePool.Name = "stale-pool"
+			staleVIP := VIP.DeepCopy()
+			staleVIP.Name = "stale-vip"
+			previous := &lbv1.ExternalLoadBalancerStatus{
+				VIPs:  []lbv1.VIP{*VIP, *staleVIP},
+				Pools: []lbv1.Pool{*pool, *stalePool},
+			}
+			err = createdBackend.HandleStaleResources(ctx, previous, []lbv1.VIP{*VIP}, []lbv1.Pool{*pool})
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("Should remove the monitor renamed by the naming template", func() {
+			lbProvider := loadBalancer.Spec.Provider
+			lbProvider.Vendor = "MonitorDrift"
+			createdBackend, err := CreateBackend(ctx, &lbProvider, "username", "password")
+			Expect(err).ShouldNot(HaveOccurred())
+			provider := createdBackend.Provider.(*monitorProvider)
+
+			current := monitor
+			current.Name = "Monitor-test"
+			previous := &lbv1.ExternalLoadBalancerStatus{Monitor: current}
+			Expect(createdBackend.HandleStaleMonitor(ctx, previous, &current)).To(Succeed())
+			Expect(provider.deleted).To(BeEmpty())
+
+			renamed := current
+			renamed.Name = "cluster-Monitor-test"
+			Expect(createdBackend.HandleStaleMonitor(ctx, previous, &renamed)).To(Succeed())
+			Expect(provider.deleted).To(Equal([]string{"Monitor-test"}))
+		})
+
 		It("Should handle a provider cleanup", func() {
 			createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 			Expect(err).ShouldNot(HaveOccurred())
@@ -195,5 +897,73 @@ var _ = Describe("Controllers/Backend/controller/backend_controller", func() {
 			output := ContainsMember(a, *m2)
 			Expect(output).To(BeFalse())
 		})
+
+		It("Should check if array contains VIP", func() {
+			v2 := VIP.DeepCopy()
+			v2.Name = "test-vip-2"
+			a := []lbv1.VIP{*VIP}
+			Expect(ContainsVIP(a, *VIP)).To(BeTrue())
+			Expect(ContainsVIP(a, *v2)).To(BeFalse())
+		})
+
+		It("Should check if array contains pool", func() {
+			p2 := pool.DeepCopy()
+			p2.Name = "test-pool-2"
+			a := []lbv1.Pool{*pool}
+			Expect(ContainsPool(a, *pool)).To(BeTrue())
+			Expect(ContainsPool(a, *p2)).To(BeFalse())
+		})
+
+		It("Should name the backend objects with the default naming template", func() {
+			namer, err := NewNamer("", NamingRules{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(namer.Name(NameData{Kind: KindMonitor, Namespace: "default", Name: "master"})).To(Equal("Monitor-default-master"))
+			Expect(namer.Name(NameData{Kind: KindPool, Namespace: "default", Name: "master", Port: "443"})).To(Equal("Pool-default-master-443"))
+			Expect(namer.Name(NameData{Kind: KindVIP, Namespace: "default", Name: "master", Port: "53-udp-ipv6"})).To(Equal("VIP-default-master-53-udp-ipv6"))
+
+			By("Keeping the names of the legacy naming template")
+			legacy, err := NewNamer(LegacyNamingTemplate, NamingRules{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(legacy.Name(NameData{Kind: KindPool, Namespace: "default", Name: "master", Port: "443"})).To(Equal("Pool-master-443"))
+		})
+
+		It("Should name the backend objects with a custom naming template and provider rules", func() {
+			namer, err := NewNamer("{{.ClusterID}}_{{.Kind}}_{{.Namespace}}/{{.Name}}_{{.Port}}",
+				NamingRules{Invalid: regexp.MustCompile(`[^A-Za-z0-9_-]`), MaxLength: 24})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(namer.Name(NameData{Kind: KindPool, ClusterID: "c1", Namespace: "ns", Name: "app", Port: "443"})).To(Equal("c1_Pool_ns-app_443"))
+
+			long, err := namer.Name(NameData{Kind: KindPool, ClusterID: "c1", Namespace: "namespace", Name: "application", Port: "443"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(long).To(HaveLen(24))
+			Expect(long).To(HavePrefix("c1_Pool_namesp"))
+			other, err := namer.Name(NameData{Kind: KindPool, ClusterID: "c1", Namespace: "namespace", Name: "application", Port: "8443"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(other).To(HaveLen(24))
+			Expect(other).NotTo(Equal(long))
+		})
+
+		It("Should refuse naming templates without unique names", func() {
+			_, err := NewNamer("{{.Kind}}-{{.Name}}", NamingRules{})
+			Expect(err).Should(HaveOccurred())
+			_, err = NewNamer("{{.Kind}}-{{.Name}}-{{.Unknown}}", NamingRules{})
+			Expect(err).Should(HaveOccurred())
+			_, err = NewNamer("{{.Kind", NamingRules{})
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("Should return an error when the template fails with the instance data", func() {
+			namer, err := NewNamer(`{{if eq .Name "empty"}}{{else}}{{.Kind}}-{{.Name}}{{if .Port}}-{{.Port}}{{end}}{{end}}`, NamingRules{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(namer.Name(NameData{Kind: KindPool, Name: "app", Port: "443"})).To(Equal("Pool-app-443"))
+			_, err = namer.Name(NameData{Kind: KindPool, Name: "empty", Port: "443"})
+			Expect(err).Should(MatchError(ContainSubstring("empty Pool name")))
+
+			// The sample data has a four character name
+			namer, err = NewNamer(`{{.Kind}}-{{slice .Name 0 4}}{{if .Port}}-{{.Port}}{{end}}`, NamingRules{})
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = namer.Name(NameData{Kind: KindPool, Name: "app", Port: "443"})
+			Expect(err).Should(MatchError(ContainSubstring("invalid naming template")))
+		})
 	})
 })
@@ -0,0 +1,161 @@
+/*
+MIT License
+
+Copyright (c) 2022 Carlos Eduardo de Paula
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"text/template"
+)
+
+// DefaultNamingTemplate is the template of the backend object names, like "Pool-default-myapp-443"
+const DefaultNamingTemplate = `{{.Kind}}-{{.Namespace}}-{{.Name}}{{if .Port}}-{{.Port}}{{end}}`
+
+// LegacyNamingTemplate is the previous DefaultNamingTemplate without the namespace, like "Pool-myapp-443".
+// It's kept by the instances applied with it so upgrading the operator doesn't rename their objects.
+const LegacyNamingTemplate = `{{.Kind}}-{{.Name}}{{if .Port}}-{{.Port}}{{end}}`
+
+// Kinds of the backend objects named by the naming template
+const (
+	KindMonitor = "Monitor"
+	KindPool    = "Pool"
+	KindVIP     = "VIP"
+)
+
+// NameData is the data available in the naming template
+type NameData struct {
+	// Kind is the backend object kind, Monitor, Pool or VIP
+	Kind string
+	// ClusterID is the cluster ID set by the operator --cluster-id flag
+	ClusterID string
+	// Namespace is the ExternalLoadBalancer namespace
+	Namespace string
+	// Name is the ExternalLoadBalancer name
+	Name string
+	// Port is the service port of pools and VIPs, suffixed with "-udp" for UDP ports and
+	// the VIP address family for dual-stack load balancers. It's empty for the monitor.
+	Port string
+}
+
+// NamingRules are the backend object naming rules of a provider
+type NamingRules struct {
+	// Invalid matches the characters not allowed in the names, replaced by "-"
+	Invalid *regexp.Regexp
+	// MaxLength is the maximum name length, longer names are truncated with a hash suffix. Zero is unlimited.
+	MaxLength int
+}
+
+// NamingRuler is implemented by the providers with backend object naming rules
+type NamingRuler interface {
+	// NamingRules returns the provider naming rules
+	NamingRules() NamingRules
+}
+
+// Namer renders the backend object names from the naming template following the provider naming rules
+type Namer struct {
+	template *template.Template
+	rules    NamingRules
+}
+
+// NewNamer parses the naming template checking it renders unique names for the monitor,
+// pools and VIPs of different ports. An empty template uses the DefaultNamingTemplate.
+func NewNamer(text string, rules NamingRules) (*Namer, error) {
+	if text == "" {
+		text = DefaultNamingTemplate
+	}
+	t, err := template.New("name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid naming template: %v", err)
+	}
+	n := &Namer{template: t, rules: rules}
+
+	samples := []NameData{
+		{Kind: KindMonitor},
+		{Kind: KindPool, Port: "80"},
+		{Kind: KindPool, Port: "443"},
+		{Kind: KindVIP, Port: "80"},
+		{Kind: KindVIP, Port: "443"},
+	}
+	names := make(map[string]bool, len(samples))
+	for _, s := range samples {
+		s.ClusterID, s.Namespace, s.Name = "cluster", "namespace", "name"
+		name, err := n.render(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid naming template: %v", err)
+		}
+		if name == "" || names[name] {
+			return nil, fmt.Errorf("invalid naming template: it must render unique names for each kind and port, use .Kind and .Port")
+		}
+		names[name] = true
+	}
+	return n, nil
+}
+
+// Name returns the backend object name for the data. The template was checked by NewNamer
+// with sample data but it can still fail or render an empty name with the instance data.
+func (n *Namer) Name(data NameData) (string, error) {
+	name, err := n.render(data)
+	if err != nil {
+		return "", fmt.Errorf("invalid naming template: %v", err)
+	}
+	if name == "" {
+		return "", fmt.Errorf("invalid naming template: it renders an empty %s name", data.Kind)
+	}
+	return n.sanitize(name), nil
+}
+
+func (n *Namer) render(data NameData) (string, error) {
+	var buf bytes.Buffer
+	if err := n.template.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// sanitize replaces the invalid characters and truncates the name to the maximum length
+// adding a hash of the full name so truncated names are still unique
+func (n *Namer) sanitize(name string) string {
+	if n.rules.Invalid != nil {
+		name = n.rules.Invalid.ReplaceAllString(name, "-")
+	}
+	if n.rules.MaxLength > 0 && len(name) > n.rules.MaxLength {
+		sum := sha256.Sum256([]byte(name))
+		hash := hex.EncodeToString(sum[:])[:8]
+		name = name[:max(n.rules.MaxLength-len(hash)-1, 0)] + "-" + hash
+	}
+	return name
+}
+
+// Namer returns a Namer for the naming template following the provider naming rules
+func (b *BackendController) Namer(text string) (*Namer, error) {
+	var rules NamingRules
+	if r, ok := unwrapProvider(b.Provider).(NamingRuler); ok {
+		rules = r.NamingRules()
+	}
+	return NewNamer(text, rules)
+}
@@ -0,0 +1,160 @@
+/*
+MIT License
+
+Copyright (c) 2022 Carlos Eduardo de Paula
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	lbv1 "github.com/carlosedp/lbconfig-operator/api/v1"
+)
+
+// Discarder is implemented by the providers that can close the connection discarding
+// the pending changes, like a HAProxy transaction. It's used by the plan mode.
+type Discarder interface {
+	// Discard closes the connection to the backend provider without applying the changes
+	Discard(context.Context) error
+}
+
+// planProvider wraps a ProviderV2 only running the Get methods. The mutating methods
+// record the change they would perform instead of calling the backend.
+type planProvider struct {
+	provider ProviderV2
+	changes  []string
+}
+
+// Plan makes the BackendController only read the backend configuration. The changes the
+// Handle methods would perform are returned by PlannedChanges instead of being applied.
+func (b *BackendController) Plan() {
+	if _, ok := b.Provider.(*planProvider); ok {
+		return
+	}
+	b.Provider = &planProvider{provider: b.Provider}
+}
+
+// PlannedChanges returns the changes recorded in plan mode
+func (b *BackendController) PlannedChanges() []string {
+	if p, ok := b.Provider.(*planProvider); ok {
+		return p.changes
+	}
+	return nil
+}
+
+// planning returns true if the BackendController is in plan mode
+func (b *BackendController) planning() bool {
+	_, ok := b.Provider.(*planProvider)
+	return ok
+}
+
+// Unwrap returns the wrapped provider
+func (p *planProvider) Unwrap() ProviderV2 {
+	return p.provider
+}
+
+func (p *planProvider) record(format string, args ...interface{}) error {
+	p.changes = append(p.changes, fmt.Sprintf(format, args...))
+	return nil
+}
+
+func (p *planProvider) Create(ctx context.Context, lbBackend lbv1.Provider, username string, password string) error {
+	return p.provider.Create(ctx, lbBackend, username, password)
+}
+
+func (p *planProvider) Connect(ctx context.Context) error {
+	return p.provider.Connect(ctx)
+}
+
+// Close discards the pending changes if supported by the provider. Close is not called
+// since it could commit or save the backend configuration.
+func (p *planProvider) Close(ctx context.Context) error {
+	if d, ok := unwrapProvider(p.provider).(Discarder); ok {
+		return d.Discard(ctx)
+	}
+	return nil
+}
+
+func (p *planProvider) GetMonitor(ctx context.Context, m *lbv1.Monitor) (*lbv1.Monitor, error) {
+	return p.provider.GetMonitor(ctx, m)
+}
+
+func (p *planProvider) CreateMonitor(_ context.Context, m *lbv1.Monitor) error {
+	return p.record("create monitor %s", m.Name)
+}
+
+func (p *planProvider) EditMonitor(_ context.Context, m *lbv1.Monitor) error {
+	return p.record("edit monitor %s", m.Name)
+}
+
+func (p *planProvider) DeleteMonitor(_ context.Context, m *lbv1.Monitor) error {
+	return p.record("delete monitor %s", m.Name)
+}
+
+func (p *planProvider) GetPool(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
+	return p.provider.GetPool(ctx, pool)
+}
+
+func (p *planProvider) CreatePool(_ context.Context, pool *lbv1.Pool) error {
+	return p.record("create pool %s", pool.Name)
+}
+
+func (p *planProvider) EditPool(_ context.Context, pool *lbv1.Pool) error {
+	return p.record("edit pool %s", pool.Name)
+}
+
+func (p *planProvider) DeletePool(_ context.Context, pool *lbv1.Pool) error {
+	return p.record("delete pool %s", pool.Name)
+}
+
+func (p *planProvider) GetPoolMembers(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
+	return p.provider.GetPoolMembers(ctx, pool)
+}
+
+func (p *planProvider) CreatePoolMember(_ context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
+	return p.record("add member %s:%d to pool %s", m.Node.Host, m.Port, pool.Name)
+}
+
+func (p *planProvider) EditPoolMember(_ context.Context, m *lbv1.PoolMember, pool *lbv1.Pool, status string) error {
+	return p.record("%s member %s:%d in pool %s", status, m.Node.Host, m.Port, pool.Name)
+}
+
+func (p *planProvider) DeletePoolMember(_ context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
+	return p.record("remove member %s:%d from pool %s", m.Node.Host, m.Port, pool.Name)
+}
+
+func (p *planProvider) GetVIP(ctx context.Context, v *lbv1.VIP) (*lbv1.VIP, error) {
+	return p.provider.GetVIP(ctx, v)
+}
+
+func (p *planProvider) CreateVIP(_ context.Context, v *lbv1.VIP) error {
+	return p.record("create VIP %s (%s:%d)", v.Name, v.IP, v.Port)
+}
+
+func (p *planProvider) EditVIP(_ context.Context, v *lbv1.VIP) error {
+	return p.record("edit VIP %s (%s:%d)", v.Name, v.IP, v.Port)
+}
+
+func (p *planProvider) DeleteVIP(_ context.Context, v *lbv1.VIP) error {
+	return p.record("delete VIP %s", v.Name)
+}
@@ -0,0 +1,176 @@
+/*
+MIT License
+
+Copyright (c) 2022 Carlos Eduardo de Paula
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controller
+
+import (
+	"context"
+
+	lbv1 "github.com/carlosedp/lbconfig-operator/api/v1"
+)
+
+// providerAdapter wraps a legacy Provider so it can be used as a ProviderV2.
+// The context is only forwarded to Create since the other legacy methods don't accept it,
+// they return the context error instead of calling the provider once it's cancelled.
+// The optional interfaces like Versioner are checked on the legacy provider by unwrapProvider.
+type providerAdapter struct {
+	provider Provider
+}
+
+// AdaptProvider wraps a legacy Provider implementing the ProviderV2 interface
+func AdaptProvider(p Provider) ProviderV2 {
+	return &providerAdapter{provider: p}
+}
+
+// Unwrap returns the wrapped legacy provider
+func (a *providerAdapter) Unwrap() Provider {
+	return a.provider
+}
+
+func (a *providerAdapter) Create(ctx context.Context, lbBackend lbv1.Provider, username string, password string) error {
+	return a.provider.Create(ctx, lbBackend, username, password)
+}
+
+func (a *providerAdapter) Connect(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.provider.Connect()
+}
+
+func (a *providerAdapter) Close(_ context.Context) error {
+	return a.provider.Close()
+}
+
+func (a *providerAdapter) GetMonitor(ctx context.Context, m *lbv1.Monitor) (*lbv1.Monitor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.provider.GetMonitor(m)
+}
+
+func (a *providerAdapter) CreateMonitor(ctx context.Context, m *lbv1.Monitor) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.provider.CreateMonitor(m)
+}
+
+func (a *providerAdapter) EditMonitor(ctx context.Context, m *lbv1.Monitor) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.provider.EditMonitor(m)
+}
+
+func (a *providerAdapter) DeleteMonitor(ctx context.Context, m *lbv1.Monitor) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.provider.DeleteMonitor(m)
+}
+
+func (a *providerAdapter) GetPool(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.provider.GetPool(pool)
+}
+
+func (a *providerAdapter) CreatePool(ctx context.Context, pool *lbv1.Pool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.provider.CreatePool(pool)
+}
+
+func (a *providerAdapter) EditPool(ctx context.Context, pool *lbv1.Pool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.provider.EditPool(pool)
+}
+
+func (a *providerAdapter) DeletePool(ctx context.Context, pool *lbv1.Pool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.provider.DeletePool(pool)
+}
+
+func (a *providerAdapter) GetPoolMembers(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.provider.GetPoolMembers(pool)
+}
+
+func (a *providerAdapter) CreatePoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.provider.CreatePoolMember(m, pool)
+}
+
+func (a *providerAdapter) EditPoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool, status string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.provider.EditPoolMember(m, pool, status)
+}
+
+func (a *providerAdapter) DeletePoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.provider.DeletePoolMember(m, pool)
+}
+
+func (a *providerAdapter) GetVIP(ctx context.Context, v *lbv1.VIP) (*lbv1.VIP, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.provider.GetVIP(v)
+}
+
+func (a *providerAdapter) CreateVIP(ctx context.Context, v *lbv1.VIP) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.provider.CreateVIP(v)
+}
+
+func (a *providerAdapter) EditVIP(ctx context.Context, v *lbv1.VIP) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.provider.EditVIP(v)
+}
+
+func (a *providerAdapter) DeleteVIP(ctx context.Context, v *lbv1.VIP) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.provider.DeleteVIP(v)
+}
@@ -49,7 +49,7 @@ type DummyProvider struct {
 }
 
 func init() {
-	err := backend.RegisterProvider("Dummy", new(DummyProvider))
+	err := backend.RegisterProviderV2("Dummy", func() backend.ProviderV2 { return new(DummyProvider) })
 	if err != nil {
 		panic(err)
 	}
@@ -65,7 +65,7 @@ func (p *DummyProvider) Create(ctx context.Context, lbBackend lbv1.Provider, use
 	p.username = username
 	p.password = password
 
-	err := p.Connect()
+	err := p.Connect(ctx)
 	if err != nil {
 		return err
 	}
@@ -73,14 +73,20 @@ func (p *DummyProvider) Create(ctx context.Context, lbBackend lbv1.Provider, use
 }
 
 // Connect creates a connection to the IP Load Balancer
-func (p *DummyProvider) Connect() error {
+func (p *DummyProvider) Connect(ctx context.Context) error {
 	host := p.host + ":" + strconv.Itoa(p.hostport)
 	p.log.Info("Connect to dummy backend request", "host", host)
 	return nil
 }
 
+// Version returns the dummy backend version
+func (p *DummyProvider) Version(ctx context.Context) (string, error) {
+	p.log.Info("Get dummy backend version")
+	return "dummy", nil
+}
+
 // Close closes the connection to the IP Load Balancer
-func (p *DummyProvider) Close() error {
+func (p *DummyProvider) Close(ctx context.Context) error {
 	p.log.Info("Close connection to dummy backend")
 	return nil
 }
@@ -90,7 +96,7 @@ func (p *DummyProvider) Close() error {
 // ----------------------------------------
 
 // GetMonitor gets a monitor in the IP Load Balancer
-func (p *DummyProvider) GetMonitor(monitor *lbv1.Monitor) (*lbv1.Monitor, error) {
+func (p *DummyProvider) GetMonitor(ctx context.Context, monitor *lbv1.Monitor) (*lbv1.Monitor, error) {
 	p.log.Info("Get dummy backend monitor objects")
 
 	mon := &lbv1.Monitor{
@@ -98,6 +104,7 @@ func (p *DummyProvider) GetMonitor(monitor *lbv1.Monitor) (*lbv1.Monitor, error)
 		MonitorType: "http",
 		Path:        "/",
 		Port:        1234,
+		Owner:       monitor.Owner,
 	}
 	p.log.Info("Get dummy backend monitor objects", "monitor", mon)
 	return mon, nil
@@ -105,20 +112,20 @@ func (p *DummyProvider) GetMonitor(monitor *lbv1.Monitor) (*lbv1.Monitor, error)
 
 // CreateMonitor creates a monitor in the IP Load Balancer
 // if port argument is 0, no port override is configured
-func (p *DummyProvider) CreateMonitor(m *lbv1.Monitor) error {
+func (p *DummyProvider) CreateMonitor(ctx context.Context, m *lbv1.Monitor) error {
 	p.log.Info("Request to create a monitor in the dummy backend", "monitor", m)
 	return nil
 }
 
 // EditMonitor edits a monitor in the IP Load Balancer
 // if port argument is 0, no port override is configured
-func (p *DummyProvider) EditMonitor(m *lbv1.Monitor) error {
+func (p *DummyProvider) EditMonitor(ctx context.Context, m *lbv1.Monitor) error {
 	p.log.Info("Request to edit a monitor in the dummy backend", "monitor", m)
 	return nil
 }
 
 // DeleteMonitor deletes a monitor in the IP Load Balancer
-func (p *DummyProvider) DeleteMonitor(m *lbv1.Monitor) error {
+func (p *DummyProvider) DeleteMonitor(ctx context.Context, m *lbv1.Monitor) error {
 	p.log.Info("Request to delete a monitor in the dummy backend", "monitor", m)
 	return nil
 }
@@ -128,26 +135,26 @@ func (p *DummyProvider) DeleteMonitor(m *lbv1.Monitor) error {
 // ----------------------------------------
 
 // GetPool gets a server pool from the Load Balancer
-func (p *DummyProvider) GetPool(pool *lbv1.Pool) (*lbv1.Pool, error) {
+func (p *DummyProvider) GetPool(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
 	p.log.Info("Get dummy backend server pool")
 
 	return nil, nil
 }
 
 // CreatePool creates a server pool in the Load Balancer
-func (p *DummyProvider) CreatePool(pool *lbv1.Pool) error {
+func (p *DummyProvider) CreatePool(ctx context.Context, pool *lbv1.Pool) error {
 	p.log.Info("Creating Pool", "pool", pool.Name)
 	return nil
 }
 
 // EditPool modifies a server pool in the Load Balancer
-func (p *DummyProvider) EditPool(pool *lbv1.Pool) error {
+func (p *DummyProvider) EditPool(ctx context.Context, pool *lbv1.Pool) error {
 	p.log.Info("Editing Pool", "pool", pool.Name)
 	return nil
 }
 
 // DeletePool removes a server pool in the Load Balancer
-func (p *DummyProvider) DeletePool(pool *lbv1.Pool) error {
+func (p *DummyProvider) DeletePool(ctx context.Context, pool *lbv1.Pool) error {
 	p.log.Info("Deleting Pool", "pool", pool.Name)
 	return nil
 }
@@ -156,27 +163,27 @@ func (p *DummyProvider) DeletePool(pool *lbv1.Pool) error {
 // Pool Member Management
 // ----------------------------------------
 
-func (p *DummyProvider) GetPoolMembers(pool *lbv1.Pool) (*lbv1.Pool, error) {
+func (p *DummyProvider) GetPoolMembers(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
 	p.log.Info("Get dummy backend server pool")
 
 	return nil, nil
 }
 
 // GetPoolMembers gets the pool members and return them in Pool object
-func (p *DummyProvider) CreatePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool) error {
+func (p *DummyProvider) CreatePoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
 	p.log.Info("Creating Node", "node", m.Node.Name, "host", m.Node.Host)
 	return nil
 }
 
 // EditPoolMember modifies a server pool member in the Load Balancer
 // status could be "enable" or "disable"
-func (p *DummyProvider) EditPoolMember(m *lbv1.PoolMember, pool *lbv1.Pool, status string) error {
+func (p *DummyProvider) EditPoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool, status string) error {
 	p.log.Info("Editing pool member", "node", m.Node.Name, "host", m.Node.Host)
 	return nil
 }
 
 // DeletePoolMember deletes a member in the Load Balancer
-func (p *DummyProvider) DeletePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool) error {
+func (p *DummyProvider) DeletePoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
 	p.log.Info("Deleting pool member", "node", m.Node.Name, "host", m.Node.Host)
 	return nil
 }
@@ -186,25 +193,25 @@ func (p *DummyProvider) DeletePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool) er
 // ----------------------------------------
 
 // GetVIP gets a VIP in the IP Load Balancer
-func (p *DummyProvider) GetVIP(v *lbv1.VIP) (*lbv1.VIP, error) {
+func (p *DummyProvider) GetVIP(ctx context.Context, v *lbv1.VIP) (*lbv1.VIP, error) {
 	p.log.Info("Get dummy backend VIP")
 	return nil, nil
 }
 
 // CreateVIP creates a Virtual Server in the Load Balancer
-func (p *DummyProvider) CreateVIP(v *lbv1.VIP) error {
+func (p *DummyProvider) CreateVIP(ctx context.Context, v *lbv1.VIP) error {
 	p.log.Info("Creating VIP", "vip", v.Name)
 	return nil
 }
 
 // EditVIP modifies a Virtual Server in the Load Balancer
-func (p *DummyProvider) EditVIP(v *lbv1.VIP) error {
+func (p *DummyProvider) EditVIP(ctx context.Context, v *lbv1.VIP) error {
 	p.log.Info("Editing VIP", "vip", v.Name)
 	return nil
 }
 
 // DeleteVIP deletes a Virtual Server in the Load Balancer
-func (p *DummyProvider) DeleteVIP(v *lbv1.VIP) error {
+func (p *DummyProvider) DeleteVIP(ctx context.Context, v *lbv1.VIP) error {
 	p.log.Info("Deleting VIP", "vip", v.Name)
 	return nil
 }
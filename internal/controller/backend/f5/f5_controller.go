@@ -27,7 +27,9 @@ package f5
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -47,6 +49,7 @@ import (
 type F5Provider struct {
 	log           logr.Logger
 	f5            *bigip.BigIP
+	transport     *backend.ContextTransport
 	host          string
 	hostport      int
 	username      string
@@ -57,7 +60,7 @@ type F5Provider struct {
 }
 
 func init() {
-	err := backend.RegisterProvider("F5_BigIP", new(F5Provider))
+	err := backend.RegisterProviderV2("F5_BigIP", func() backend.ProviderV2 { return new(F5Provider) })
 	if err != nil {
 		panic(err)
 	}
@@ -65,18 +68,20 @@ func init() {
 
 var LBMethodMap = map[string]string{"ROUNDROBIN": "round-robin", "LEASTCONNECTION": "least-connections-member", "LEASTRESPONSETIME": "fastest-app-response"}
 
+// invalidName matches the characters not allowed in the F5 object names
+var invalidName = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// NamingRules returns the F5 object naming rules
+func (p *F5Provider) NamingRules() backend.NamingRules {
+	return backend.NamingRules{Invalid: invalidName, MaxLength: 255}
+}
+
 // Create creates a new Load Balancer backend provider
 func (p *F5Provider) Create(ctx context.Context, lbBackend lbv1.Provider, username string, password string) error {
 	log := ctrllog.FromContext(ctx).WithValues("provider", "F5_BigIP")
 
-	if lbBackend.Partition == "" {
-		p.partition = "/Common/"
-		p.log.Info("Partition not set, will use default as /Common")
-	} else {
-		p.partition = "/" + lbBackend.Partition + "/"
-	}
-
 	p.log = log
+	p.partition = "/" + lbBackend.F5Partition() + "/"
 	p.host = lbBackend.Host
 	p.hostport = lbBackend.Port
 	p.username = username
@@ -88,17 +93,41 @@ func (p *F5Provider) Create(ctx context.Context, lbBackend lbv1.Provider, userna
 }
 
 // Connect creates a connection to the IP Load Balancer
-func (p *F5Provider) Connect() error {
+func (p *F5Provider) Connect(ctx context.Context) error {
 
 	c, _ := url.Parse(p.host)
 	host := c.Host + ":" + fmt.Sprintf("%d", p.hostport)
 	p.f5 = bigip.NewSession(host, p.username, p.password, nil)
 
+	// The session only takes a *http.Transport so the traced transport is registered
+	// as its protocol handler, sending all the requests through it
+	p.transport = backend.NewContextTransport(p.f5.Transport)
+	p.transport.SetContext(ctx)
+	t := &http.Transport{}
+	t.RegisterProtocol("https", p.transport)
+	t.RegisterProtocol("http", p.transport)
+	p.f5.Transport = t
+
 	return nil
 }
 
+// Version returns the software version of the F5 BigIP device
+func (p *F5Provider) Version(ctx context.Context) (string, error) {
+	if p.f5 == nil {
+		if err := p.Connect(ctx); err != nil {
+			return "", err
+		}
+	}
+	p.transport.SetContext(ctx)
+	d, err := p.f5.GetCurrentDevice()
+	if err != nil {
+		return "", fmt.Errorf("error getting F5 device: %v", err)
+	}
+	return d.Version, nil
+}
+
 // Close closes the connection to the IP Load Balancer
-func (p *F5Provider) Close() error {
+func (p *F5Provider) Close(ctx context.Context) error {
 	return nil
 }
 
@@ -107,7 +136,8 @@ func (p *F5Provider) Close() error {
 // ----------------------------------------
 
 // GetMonitor gets a monitor in the IP Load Balancer
-func (p *F5Provider) GetMonitor(monitor *lbv1.Monitor) (*lbv1.Monitor, error) {
+func (p *F5Provider) GetMonitor(ctx context.Context, monitor *lbv1.Monitor) (*lbv1.Monitor, error) {
+	p.transport.SetContext(ctx)
 	m, err := p.f5.GetMonitor(monitor.Name, monitor.MonitorType)
 	if err != nil {
 		return nil, fmt.Errorf("error getting F5 Monitor %s: %v", monitor.Name, err)
@@ -126,11 +156,26 @@ func (p *F5Provider) GetMonitor(monitor *lbv1.Monitor) (*lbv1.Monitor, error) {
 	}
 
 	parent := strings.Split(m.ParentMonitor, "/")[2]
+	method, path, host := parseSendString(m.SendString)
 	mon := &lbv1.Monitor{
 		Name:        m.Name,
 		MonitorType: parent,
-		Path:        strings.TrimLeft(m.SendString, "GET "),
+		Path:        path,
 		Port:        port,
+		Interval:    m.Interval,
+		Timeout:     m.Timeout,
+		Method:      method,
+		Host:        host,
+		Owner:       m.Description,
+	}
+	if parent == lbv1.MonitorTypeTCP {
+		mon.Path, mon.Method, mon.Host = monitor.Path, monitor.Method, monitor.Host
+		mon.Send = m.SendString
+		mon.Receive = m.ReceiveString
+	} else if code, ok := strings.CutPrefix(m.ReceiveString, statusReceivePrefix); ok {
+		mon.ExpectedStatus, _ = strconv.Atoi(code)
+	} else {
+		mon.Receive = m.ReceiveString
 	}
 
 	return mon, nil
@@ -138,15 +183,17 @@ func (p *F5Provider) GetMonitor(monitor *lbv1.Monitor) (*lbv1.Monitor, error) {
 
 // CreateMonitor creates a monitor in the IP Load Balancer
 // if port argument is 0, no port override is configured
-func (p *F5Provider) CreateMonitor(m *lbv1.Monitor) error {
-
+func (p *F5Provider) CreateMonitor(ctx context.Context, monitor *lbv1.Monitor) error {
+	p.transport.SetContext(ctx)
+	m := monitor.WithDefaults()
 	config := &bigip.Monitor{
 		Name:          m.Name,
 		ParentMonitor: p.partition + m.MonitorType,
-		Interval:      5,
-		Timeout:       16,
-		SendString:    "GET " + m.Path,
-		ReceiveString: "",
+		Interval:      m.Interval,
+		Timeout:       m.Timeout,
+		SendString:    sendString(&m),
+		ReceiveString: receiveString(&m),
+		Description:   m.Owner,
 	}
 
 	if m.Port != 0 {
@@ -163,14 +210,17 @@ func (p *F5Provider) CreateMonitor(m *lbv1.Monitor) error {
 
 // EditMonitor edits a monitor in the IP Load Balancer
 // if port argument is 0, no port override is configured
-func (p *F5Provider) EditMonitor(m *lbv1.Monitor) error {
+func (p *F5Provider) EditMonitor(ctx context.Context, monitor *lbv1.Monitor) error {
+	p.transport.SetContext(ctx)
+	m := monitor.WithDefaults()
 	config := &bigip.Monitor{
 		Name:          m.Name,
 		ParentMonitor: p.partition + m.MonitorType,
-		Interval:      5,
-		Timeout:       16,
-		SendString:    "GET " + m.Path,
-		ReceiveString: "",
+		Interval:      m.Interval,
+		Timeout:       m.Timeout,
+		SendString:    sendString(&m),
+		ReceiveString: receiveString(&m),
+		Description:   m.Owner,
 	}
 
 	// Cannot update monitor port.
@@ -187,7 +237,8 @@ func (p *F5Provider) EditMonitor(m *lbv1.Monitor) error {
 }
 
 // DeleteMonitor deletes a monitor in the IP Load Balancer
-func (p *F5Provider) DeleteMonitor(m *lbv1.Monitor) error {
+func (p *F5Provider) DeleteMonitor(ctx context.Context, m *lbv1.Monitor) error {
+	p.transport.SetContext(ctx)
 	err := p.f5.DeleteMonitor(m.Name, m.MonitorType)
 	if err != nil {
 		return fmt.Errorf("error deleting F5 monitor %s: %v", m.Name, err)
@@ -200,7 +251,8 @@ func (p *F5Provider) DeleteMonitor(m *lbv1.Monitor) error {
 // ----------------------------------------
 
 // GetPool gets a server pool from the Load Balancer
-func (p *F5Provider) GetPool(pool *lbv1.Pool) (*lbv1.Pool, error) {
+func (p *F5Provider) GetPool(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
+	p.transport.SetContext(ctx)
 
 	newPool, err := p.f5.GetPool(pool.Name)
 	if err != nil {
@@ -216,13 +268,15 @@ func (p *F5Provider) GetPool(pool *lbv1.Pool) (*lbv1.Pool, error) {
 	retPool := &lbv1.Pool{
 		Name:    newPool.Name,
 		Monitor: strings.Trim(newPool.Monitor, p.partition),
+		Owner:   newPool.Description,
 	}
 
 	return retPool, nil
 }
 
 // CreatePool creates a server pool in the Load Balancer
-func (p *F5Provider) CreatePool(pool *lbv1.Pool) error {
+func (p *F5Provider) CreatePool(ctx context.Context, pool *lbv1.Pool) error {
+	p.transport.SetContext(ctx)
 
 	// Create Pool
 	err := p.f5.CreatePool(pool.Name)
@@ -236,9 +290,10 @@ func (p *F5Provider) CreatePool(pool *lbv1.Pool) error {
 		return fmt.Errorf("error adding monitor %s to pool %s: %v", pool.Monitor, pool.Name, err)
 	}
 
-	// Set pool balancing method
+	// Set pool balancing method and owner
 	if p.f5.ModifyPool(pool.Name, &bigip.Pool{
 		LoadBalancingMode: p.lbmethod,
+		Description:       pool.Owner,
 	}) != nil {
 		return fmt.Errorf("error setting pool %s to method %s: %v", pool.Name, p.lbmethod, err)
 	}
@@ -247,11 +302,13 @@ func (p *F5Provider) CreatePool(pool *lbv1.Pool) error {
 }
 
 // EditPool modifies a server pool in the Load Balancer
-func (p *F5Provider) EditPool(pool *lbv1.Pool) error {
+func (p *F5Provider) EditPool(ctx context.Context, pool *lbv1.Pool) error {
+	p.transport.SetContext(ctx)
 	newPool := &bigip.Pool{
 		Name:              pool.Name,
 		Monitor:           pool.Monitor,
 		LoadBalancingMode: p.lbmethod,
+		Description:       pool.Owner,
 	}
 
 	err := p.f5.ModifyPool(pool.Name, newPool)
@@ -262,7 +319,8 @@ func (p *F5Provider) EditPool(pool *lbv1.Pool) error {
 }
 
 // DeletePool removes a server pool in the Load Balancer
-func (p *F5Provider) DeletePool(pool *lbv1.Pool) error {
+func (p *F5Provider) DeletePool(ctx context.Context, pool *lbv1.Pool) error {
+	p.transport.SetContext(ctx)
 	err := p.f5.DeletePool(pool.Name)
 	if err != nil {
 		return fmt.Errorf("error deleting pool %s: %v", pool.Name, err)
@@ -275,7 +333,8 @@ func (p *F5Provider) DeletePool(pool *lbv1.Pool) error {
 // ----------------------------------------
 
 // GetPoolMembers gets the pool members and return them in Pool object
-func (p *F5Provider) GetPoolMembers(pool *lbv1.Pool) (*lbv1.Pool, error) {
+func (p *F5Provider) GetPoolMembers(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
+	p.transport.SetContext(ctx)
 
 	// // Get pool members
 	members := make([]lbv1.PoolMember, 0)
@@ -284,8 +343,8 @@ func (p *F5Provider) GetPoolMembers(pool *lbv1.Pool) (*lbv1.Pool, error) {
 		return nil, fmt.Errorf("error getting F5 pool members: %v", err)
 	}
 	for _, member := range poolMembers.PoolMembers {
-		ip := strings.Split(member.Address, ":")[0]
-		port, _ := strconv.Atoi(strings.Split(member.FullPath, ":")[1])
+		ip := member.Address
+		_, port, _ := splitAddressPort(member.FullPath)
 		node := &lbv1.Node{
 			Name: member.Name,
 			Host: ip,
@@ -302,10 +361,11 @@ func (p *F5Provider) GetPoolMembers(pool *lbv1.Pool) (*lbv1.Pool, error) {
 }
 
 // CreatePoolMember creates a member to be added to pool in the Load Balancer
-func (p *F5Provider) CreatePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool) error {
+func (p *F5Provider) CreatePoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
+	p.transport.SetContext(ctx)
 	p.log.Info("Creating Node", "node", m.Node.Name, "host", m.Node.Host)
 	config := bigip.Node{
-		Name:    m.Node.Host + ":" + strconv.Itoa(m.Port),
+		Name:    joinAddressPort(m.Node.Host, m.Port),
 		Address: m.Node.Host,
 	}
 	// Query node by IP
@@ -325,7 +385,7 @@ func (p *F5Provider) CreatePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool) error
 		}
 	}
 
-	err = p.f5.AddPoolMember(pool.Name, m.Node.Host+":"+strconv.Itoa(m.Port))
+	err = p.f5.AddPoolMember(pool.Name, joinAddressPort(m.Node.Host, m.Port))
 	if err != nil {
 		return fmt.Errorf("error adding member %s to pool %s: %v", m.Node.Host, pool.Name, err)
 	}
@@ -335,8 +395,9 @@ func (p *F5Provider) CreatePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool) error
 
 // EditPoolMember modifies a server pool member in the Load Balancer
 // status could be "enable" or "disable"
-func (p *F5Provider) EditPoolMember(m *lbv1.PoolMember, pool *lbv1.Pool, status string) error {
-	err := p.f5.PoolMemberStatus(pool.Name, m.Node.Host+":"+strconv.Itoa(m.Port), status)
+func (p *F5Provider) EditPoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool, status string) error {
+	p.transport.SetContext(ctx)
+	err := p.f5.PoolMemberStatus(pool.Name, joinAddressPort(m.Node.Host, m.Port), status)
 	if err != nil {
 		return fmt.Errorf("error editing member %s in pool %s: %v", m.Node.Host, pool.Name, err)
 	}
@@ -344,9 +405,10 @@ func (p *F5Provider) EditPoolMember(m *lbv1.PoolMember, pool *lbv1.Pool, status
 }
 
 // DeletePoolMember deletes a member in the Load Balancer
-func (p *F5Provider) DeletePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool) error {
+func (p *F5Provider) DeletePoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
+	p.transport.SetContext(ctx)
 	// First delete member from pool
-	err := p.f5.DeletePoolMember(p.partition+pool.Name, m.Node.Host+":"+strconv.Itoa(m.Port))
+	err := p.f5.DeletePoolMember(p.partition+pool.Name, joinAddressPort(m.Node.Host, m.Port))
 	if err != nil {
 		return fmt.Errorf("error removing member %s from pool %s: %v", m.Node.Host, pool.Name, err)
 	}
@@ -363,7 +425,8 @@ func (p *F5Provider) DeletePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool) error
 // ----------------------------------------
 
 // GetVIP gets a VIP in the IP Load Balancer
-func (p *F5Provider) GetVIP(v *lbv1.VIP) (*lbv1.VIP, error) {
+func (p *F5Provider) GetVIP(ctx context.Context, v *lbv1.VIP) (*lbv1.VIP, error) {
+	p.transport.SetContext(ctx)
 	vs, err := p.f5.GetVirtualServer(v.Name)
 	if err != nil {
 		return nil, fmt.Errorf("error getting F5 virtualserver %s: %v", v.Name, err)
@@ -375,32 +438,34 @@ func (p *F5Provider) GetVIP(v *lbv1.VIP) (*lbv1.VIP, error) {
 	}
 
 	// Return VIP details in case it exists
-	s := strings.Split(vs.Destination, ":")
-	ip := strings.Trim(s[0], p.partition)
-	port, err := strconv.Atoi(s[1])
+	ip, port, err := splitAddressPort(vs.Destination)
 	if err != nil {
 		return nil, fmt.Errorf("error reading F5 VS port: %v", err)
 	}
 
 	vip := &lbv1.VIP{
-		Name: vs.Name,
-		IP:   ip,
-		Port: port,
-		Pool: v.Pool,
+		Name:  vs.Name,
+		IP:    ip,
+		Port:  port,
+		Pool:  v.Pool,
+		Owner: vs.Description,
 	}
 
 	return vip, nil
 }
 
 // CreateVIP creates a Virtual Server in the Load Balancer
-func (p *F5Provider) CreateVIP(v *lbv1.VIP) error {
+func (p *F5Provider) CreateVIP(ctx context.Context, v *lbv1.VIP) error {
+	p.transport.SetContext(ctx)
 	// The second parameter is our destination, and the third is the mask. You can use CIDR notation if you wish (as shown here)
 
 	config := &bigip.VirtualServer{
 		Name:        v.Name,
 		Partition:   p.partition,
-		Destination: v.IP + ":" + strconv.Itoa(v.Port),
+		Destination: joinAddressPort(v.IP, v.Port),
+		IPProtocol:  ipProtocol(v.Protocol),
 		Pool:        v.Pool,
+		Description: v.Owner,
 		SourceAddressTranslation: struct {
 			Type string "json:\"type,omitempty\""
 			Pool string "json:\"pool,omitempty\""
@@ -425,12 +490,15 @@ func (p *F5Provider) CreateVIP(v *lbv1.VIP) error {
 }
 
 // EditVIP modifies a Virtual Server in the Load Balancer
-func (p *F5Provider) EditVIP(v *lbv1.VIP) error {
+func (p *F5Provider) EditVIP(ctx context.Context, v *lbv1.VIP) error {
+	p.transport.SetContext(ctx)
 	config := &bigip.VirtualServer{
 		Name:        v.Name,
 		Partition:   p.partition,
-		Destination: v.IP + ":" + strconv.Itoa(v.Port),
+		Destination: joinAddressPort(v.IP, v.Port),
+		IPProtocol:  ipProtocol(v.Protocol),
 		Pool:        v.Pool,
+		Description: v.Owner,
 		SourceAddressTranslation: struct {
 			Type string "json:\"type,omitempty\""
 			Pool string "json:\"pool,omitempty\""
@@ -455,10 +523,88 @@ func (p *F5Provider) EditVIP(v *lbv1.VIP) error {
 }
 
 // DeleteVIP deletes a Virtual Server in the Load Balancer
-func (p *F5Provider) DeleteVIP(v *lbv1.VIP) error {
+func (p *F5Provider) DeleteVIP(ctx context.Context, v *lbv1.VIP) error {
+	p.transport.SetContext(ctx)
 	err := p.f5.DeleteVirtualServer(v.Name)
 	if err != nil {
 		return fmt.Errorf("error deleting VIP %s: %v", v.Name, err)
 	}
 	return nil
 }
+
+// ----------------------------------------
+// Helpers
+// ----------------------------------------
+
+// joinAddressPort builds the F5 "address:port" notation used in pool members and
+// virtual server destinations. IPv6 addresses use a "." as the port separator.
+// Route domains in the "%<id>" notation are kept in the address.
+func joinAddressPort(address string, port int) string {
+	if backend.IsIPv6(address) {
+		return address + "." + strconv.Itoa(port)
+	}
+	return address + ":" + strconv.Itoa(port)
+}
+
+// splitAddressPort splits a F5 destination like "/Common/10.0.0.1:443" or
+// "/Common/2001:db8::1.443" into the address and port.
+func splitAddressPort(destination string) (string, int, error) {
+	destination = destination[strings.LastIndex(destination, "/")+1:]
+	i := strings.LastIndexAny(destination, ":.")
+	if i < 0 {
+		return "", 0, fmt.Errorf("invalid F5 destination %s", destination)
+	}
+	port, err := strconv.Atoi(destination[i+1:])
+	if err != nil {
+		return "", 0, err
+	}
+	return destination[:i], port, nil
+}
+
+// ipProtocol returns the F5 virtual server IP protocol for the VIP protocol
+func ipProtocol(protocol string) string {
+	if protocol == lbv1.ProtocolUDP {
+		return "udp"
+	}
+	return "tcp"
+}
+
+// statusReceivePrefix is the F5 receive string prefix matching the expected status code in the response status line
+const statusReceivePrefix = "^HTTP/1\\.[01] "
+
+// sendString builds the F5 monitor send string. A HTTP/1.1 request is sent when the Host header is set.
+// The tcp monitors send the Send string as is.
+func sendString(m *lbv1.Monitor) string {
+	if m.MonitorType == lbv1.MonitorTypeTCP {
+		return m.Send
+	}
+	send := m.Method + " " + m.Path
+	if m.Host != "" {
+		send += " HTTP/1.1\\r\\nHost: " + m.Host + "\\r\\nConnection: Close\\r\\n\\r\\n"
+	}
+	return send
+}
+
+// parseSendString returns the method, path and Host header from a F5 monitor send string
+func parseSendString(send string) (string, string, string) {
+	request, headers, _ := strings.Cut(send, " HTTP/1.1")
+	method, path, _ := strings.Cut(request, " ")
+	var host string
+	for _, header := range strings.Split(headers, "\\r\\n") {
+		if h, ok := strings.CutPrefix(header, "Host: "); ok {
+			host = h
+		}
+	}
+	return method, path, host
+}
+
+// receiveString builds the F5 monitor receive string from the expected string or status code
+func receiveString(m *lbv1.Monitor) string {
+	if m.Receive != "" {
+		return m.Receive
+	}
+	if m.ExpectedStatus != 0 && m.MonitorType != lbv1.MonitorTypeTCP {
+		return statusReceivePrefix + strconv.Itoa(m.ExpectedStatus)
+	}
+	return ""
+}
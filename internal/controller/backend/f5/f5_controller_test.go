@@ -93,7 +93,7 @@ var loadBalancer = &lbv1.ExternalLoadBalancer{
 			Host:          "",
 			Port:          0,
 			Creds:         credsSecret.Name,
-			Partition:     "Common",
+			F5:            &lbv1.F5Options{Partition: "Common"},
 			ValidateCerts: false,
 			LBMethod:      "LEASTCONNECTION",
 		},
@@ -135,12 +135,28 @@ var poolmember = &lbv1.PoolMember{
 	Port: 80,
 }
 
+var poolmemberIPv6 = &lbv1.PoolMember{
+	Node: lbv1.Node{
+		Name:   "test-node-6",
+		Host:   "2001:db8::6",
+		Labels: map[string]string{masterNodeLabel: ""},
+	},
+	Port: 80,
+}
+
 var VIP = &lbv1.VIP{
 	Name: "test-vip",
 	Pool: pool.Name,
 	IP:   "1.2.3.4",
 }
 
+var VIPv6 = &lbv1.VIP{
+	Name: "test-vip-ipv6",
+	Pool: pool.Name,
+	IP:   "2001:db8::10",
+	Port: 443,
+}
+
 // Store the http session data for the request
 type httpdataStruct struct {
 	url    string
@@ -191,17 +207,59 @@ var _ = Describe("When using a f5 backend", func() {
 	It("Should connect to the backend", func() {
 		createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 		Expect(err).NotTo(HaveOccurred())
-		err = createdBackend.Provider.Connect()
+		err = createdBackend.Provider.Connect(ctx)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("Should close connection", func() {
 		createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 		Expect(err).NotTo(HaveOccurred())
-		err = createdBackend.Provider.Connect()
+		err = createdBackend.Provider.Connect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		err = createdBackend.Provider.Close(ctx)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should request the backend version", func() {
+		createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
+		Expect(err).ToNot(HaveOccurred())
+		_, _ = createdBackend.Version(ctx)
+		Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/cm/device"))
+		Eventually(httpdata.method, timeout, interval).Should(Equal("GET"))
+	})
+
+	It("Should not send requests after the context is cancelled", func() {
+		createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
+		Expect(err).ToNot(HaveOccurred())
+		httpdata.url = ""
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = createdBackend.Version(cancelled)
+		Expect(err).To(MatchError(ContainSubstring(context.Canceled.Error())))
+		Expect(httpdata.url).To(BeEmpty())
+	})
+
+	It("Should use the partition from the f5 options", func() {
+		provider := loadBalancer.Spec.Provider.DeepCopy()
+		provider.F5 = &lbv1.F5Options{Partition: "Tenant1"}
+		createdBackend, err := CreateBackend(ctx, provider, "username", "password")
+		Expect(err).ToNot(HaveOccurred())
+		err = createdBackend.Provider.Connect(ctx)
 		Expect(err).NotTo(HaveOccurred())
-		err = createdBackend.Provider.Close()
+		_ = createdBackend.Provider.EditVIP(ctx, VIP)
+		Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/virtual/~Tenant1~test-vip"))
+	})
+
+	It("Should use the deprecated partition when the f5 options are not set", func() {
+		provider := loadBalancer.Spec.Provider.DeepCopy()
+		provider.F5 = nil
+		provider.Partition = "Tenant2"
+		createdBackend, err := CreateBackend(ctx, provider, "username", "password")
+		Expect(err).ToNot(HaveOccurred())
+		err = createdBackend.Provider.Connect(ctx)
 		Expect(err).NotTo(HaveOccurred())
+		_ = createdBackend.Provider.EditVIP(ctx, VIP)
+		Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/virtual/~Tenant2~test-vip"))
 	})
 
 	Context("when handling load balancer monitors", func() {
@@ -210,12 +268,12 @@ var _ = Describe("When using a f5 backend", func() {
 		BeforeEach(func() {
 			createdBackend, err = CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 			Expect(err).ToNot(HaveOccurred())
-			err = createdBackend.Provider.Connect()
+			err = createdBackend.Provider.Connect(ctx)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("Should get a monitor", func() {
-			_, _ = createdBackend.Provider.GetMonitor(monitor)
+			_, _ = createdBackend.Provider.GetMonitor(ctx, monitor)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/monitor/http/test-monitor"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("GET"))
 			// Getting error "error getting F5 Monitor test-monitor: invalid character 'O' looking for beginning of value"
@@ -223,7 +281,7 @@ var _ = Describe("When using a f5 backend", func() {
 		})
 
 		It("Should create a monitor", func() {
-			err = createdBackend.Provider.CreateMonitor(monitor)
+			err = createdBackend.Provider.CreateMonitor(ctx, monitor)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/monitor/http"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("POST"))
 			// <map[string]interface {} | len:11>: {
@@ -248,15 +306,47 @@ var _ = Describe("When using a f5 backend", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("Should tag the monitor with the owner", func() {
+			m := monitor.DeepCopy()
+			m.Owner = OwnerTag("cluster", "default", "f5-backend")
+			_ = createdBackend.Provider.CreateMonitor(ctx, m)
+			Eventually(func() string { return gjson.Get(httpdata.data, "description").String() }, timeout, interval).Should(Equal(m.Owner))
+		})
+
+		It("Should create a monitor with the Host header and expected status", func() {
+			m := monitor.DeepCopy()
+			m.Host = "app.example.com"
+			m.ExpectedStatus = 200
+			m.Interval = 10
+			err = createdBackend.Provider.CreateMonitor(ctx, m)
+			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/monitor/http"))
+			Eventually(func() string { return gjson.Get(httpdata.data, "send").String() }, timeout, interval).Should(Equal(`GET /health HTTP/1.1\r\nHost: app.example.com\r\nConnection: Close\r\n\r\n`))
+			Eventually(func() string { return gjson.Get(httpdata.data, "recv").String() }, timeout, interval).Should(Equal(`^HTTP/1\.[01] 200`))
+			Eventually(func() int64 { return gjson.Get(httpdata.data, "interval").Int() }, timeout, interval).Should(Equal(int64(10)))
+			Eventually(func() int64 { return gjson.Get(httpdata.data, "timeout").Int() }, timeout, interval).Should(Equal(int64(16)))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should create a tcp monitor", func() {
+			tcpMonitor := &lbv1.Monitor{Name: "test-tcp-monitor", MonitorType: "tcp", Port: 1883, Send: "PING", Receive: "PONG"}
+			err = createdBackend.Provider.CreateMonitor(ctx, tcpMonitor)
+			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/monitor/tcp"))
+			Eventually(httpdata.method, timeout, interval).Should(Equal("POST"))
+			Eventually(func() string { return gjson.Get(httpdata.data, "defaultsFrom").String() }, timeout, interval).Should(Equal("/Common/tcp"))
+			Eventually(func() string { return gjson.Get(httpdata.data, "send").String() }, timeout, interval).Should(Equal("PING"))
+			Eventually(func() string { return gjson.Get(httpdata.data, "recv").String() }, timeout, interval).Should(Equal("PONG"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
 		It("Should delete the monitor", func() {
-			err = createdBackend.Provider.DeleteMonitor(monitor)
+			err = createdBackend.Provider.DeleteMonitor(ctx, monitor)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/monitor/http/test-monitor"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("DELETE"))
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("Should edit the monitor", func() {
-			err = createdBackend.Provider.EditMonitor(monitor)
+			err = createdBackend.Provider.EditMonitor(ctx, monitor)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/monitor/http/test-monitor"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("PATCH"))
 
@@ -275,17 +365,17 @@ var _ = Describe("When using a f5 backend", func() {
 		BeforeEach(func() {
 			createdBackend, err = CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 			Expect(err).ToNot(HaveOccurred())
-			err = createdBackend.Provider.Connect()
+			err = createdBackend.Provider.Connect(ctx)
 			Expect(err).NotTo(HaveOccurred())
 		})
 		It("Should get a pool", func() {
-			_, _ = createdBackend.Provider.GetPool(pool)
+			_, _ = createdBackend.Provider.GetPool(ctx, pool)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/pool/test-pool"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("GET"))
 		})
 
 		It("Should create a pool", func() {
-			err = createdBackend.Provider.CreatePool(pool)
+			err = createdBackend.Provider.CreatePool(ctx, pool)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/pool/test-pool"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("PUT"))
 			Eventually(func() string { return gjson.Get(httpdata.data, "loadBalancingMode").String() }, timeout, interval).Should(Equal("least-connections-member"))
@@ -293,14 +383,14 @@ var _ = Describe("When using a f5 backend", func() {
 		})
 
 		It("Should delete the pool", func() {
-			err = createdBackend.Provider.DeletePool(pool)
+			err = createdBackend.Provider.DeletePool(ctx, pool)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/pool/test-pool"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("DELETE"))
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("Should edit the pool", func() {
-			err = createdBackend.Provider.EditPool(pool)
+			err = createdBackend.Provider.EditPool(ctx, pool)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/pool/test-pool"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("PUT"))
 			Eventually(func() string { return gjson.Get(httpdata.data, "name").String() }, timeout, interval).Should(Equal("test-pool"))
@@ -308,21 +398,21 @@ var _ = Describe("When using a f5 backend", func() {
 		})
 
 		It("Should get pool members", func() {
-			_, _ = createdBackend.Provider.GetPoolMembers(pool)
+			_, _ = createdBackend.Provider.GetPoolMembers(ctx, pool)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/pool/test-pool/members"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("GET"))
 			// Getting error "error getting F5 Monitor test-monitor: invalid character 'O' looking for beginning of value"
 			// Expect(err).NotTo(HaveOccurred())
 		})
 		It("Should create pool members", func() {
-			_ = createdBackend.Provider.CreatePoolMember(poolmember, pool)
+			_ = createdBackend.Provider.CreatePoolMember(ctx, poolmember, pool)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/node/1.1.1.5"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("GET"))
 			// Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("Should delete pool members", func() {
-			err = createdBackend.Provider.DeletePoolMember(poolmember, pool)
+			err = createdBackend.Provider.DeletePoolMember(ctx, poolmember, pool)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/pool/~Common~test-pool/members/1.1.1.5:80"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("DELETE"))
 			Expect(err).NotTo(HaveOccurred())
@@ -330,19 +420,25 @@ var _ = Describe("When using a f5 backend", func() {
 
 		It("Should edit pool members", func() {
 			// Enable
-			err = createdBackend.Provider.EditPoolMember(poolmember, pool, "enable")
+			err = createdBackend.Provider.EditPoolMember(ctx, poolmember, pool, "enable")
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/pool/test-pool/members/1.1.1.5:80"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("PUT"))
 			Eventually(func() string { return gjson.Get(httpdata.data, "session").String() }, timeout, interval).Should(Equal("user-enabled"))
 			Expect(err).NotTo(HaveOccurred())
 			// Disable
-			err = createdBackend.Provider.EditPoolMember(poolmember, pool, "disable")
+			err = createdBackend.Provider.EditPoolMember(ctx, poolmember, pool, "disable")
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/pool/test-pool/members/1.1.1.5:80"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("PUT"))
 			// Expect(httpdata.data).Should(Equal(""))
 			Eventually(func() string { return gjson.Get(httpdata.data, "session").String() }, timeout, interval).Should(Equal("user-disabled"))
 			Expect(err).NotTo(HaveOccurred())
 		})
+		It("Should use the F5 IPv6 notation for pool members", func() {
+			err = createdBackend.Provider.DeletePoolMember(ctx, poolmemberIPv6, pool)
+			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/pool/~Common~test-pool/members/2001:db8::6.80"))
+			Eventually(httpdata.method, timeout, interval).Should(Equal("DELETE"))
+			Expect(err).NotTo(HaveOccurred())
+		})
 	})
 
 	Context("when handling load balancer VIPs", func() {
@@ -351,18 +447,18 @@ var _ = Describe("When using a f5 backend", func() {
 		BeforeEach(func() {
 			createdBackend, err = CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 			Expect(err).ToNot(HaveOccurred())
-			err = createdBackend.Provider.Connect()
+			err = createdBackend.Provider.Connect(ctx)
 			Expect(err).NotTo(HaveOccurred())
 		})
 		It("Should get a VIP", func() {
-			_, _ = createdBackend.Provider.GetVIP(VIP)
+			_, _ = createdBackend.Provider.GetVIP(ctx, VIP)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/virtual/test-vip"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("GET"))
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("Should create a VIP", func() {
-			err = createdBackend.Provider.CreateVIP(VIP)
+			err = createdBackend.Provider.CreateVIP(ctx, VIP)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/virtual"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("POST"))
 
@@ -371,15 +467,42 @@ var _ = Describe("When using a f5 backend", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("Should create an IPv6 VIP", func() {
+			err = createdBackend.Provider.CreateVIP(ctx, VIPv6)
+			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/virtual"))
+			Eventually(httpdata.method, timeout, interval).Should(Equal("POST"))
+			Eventually(func() string { return gjson.Get(httpdata.data, "destination").String() }, timeout, interval).Should(Equal("2001:db8::10.443"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should tag the VIP with the owner", func() {
+			ownedVIP := VIP.DeepCopy()
+			ownedVIP.Owner = OwnerTag("cluster", "default", "f5-backend")
+			err = createdBackend.Provider.CreateVIP(ctx, ownedVIP)
+			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/virtual"))
+			Eventually(func() string { return gjson.Get(httpdata.data, "description").String() }, timeout, interval).Should(Equal("lbconfig-operator:cluster/default/f5-backend"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should create an UDP VIP", func() {
+			udpVIP := VIP.DeepCopy()
+			udpVIP.Protocol = lbv1.ProtocolUDP
+			err = createdBackend.Provider.CreateVIP(ctx, udpVIP)
+			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/virtual"))
+			Eventually(httpdata.method, timeout, interval).Should(Equal("POST"))
+			Eventually(func() string { return gjson.Get(httpdata.data, "ipProtocol").String() }, timeout, interval).Should(Equal("udp"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
 		It("Should delete the VIP", func() {
-			err = createdBackend.Provider.DeleteVIP(VIP)
+			err = createdBackend.Provider.DeleteVIP(ctx, VIP)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/virtual/test-vip"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("DELETE"))
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("Should edit the VIP", func() {
-			err = createdBackend.Provider.EditVIP(VIP)
+			err = createdBackend.Provider.EditVIP(ctx, VIP)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/virtual/~Common~test-vip"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("PATCH"))
 			Eventually(func() string { return gjson.Get(httpdata.data, "name").String() }, timeout, interval).Should(Equal("test-vip"))
@@ -28,20 +28,25 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/carlosedp/haproxy-go-client/client"
 	"github.com/carlosedp/haproxy-go-client/client/backend"
 	"github.com/carlosedp/haproxy-go-client/client/bind"
 	"github.com/carlosedp/haproxy-go-client/client/frontend"
+	"github.com/carlosedp/haproxy-go-client/client/information"
 	"github.com/carlosedp/haproxy-go-client/client/server"
 	"github.com/carlosedp/haproxy-go-client/client/sites"
+	"github.com/carlosedp/haproxy-go-client/client/tcp_check"
 	"github.com/carlosedp/haproxy-go-client/client/transactions"
 	"github.com/go-logr/logr"
 	"github.com/go-openapi/runtime"
 	httptransport "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
 	"github.com/haproxytech/client-native/v4/models"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"k8s.io/utils/ptr"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -65,12 +70,11 @@ type HAProxyProvider struct {
 	transaction string
 	version     int64
 	monitor     lbv1.Monitor
-	ctx         context.Context
 	lbmethod    string
 }
 
 func init() {
-	err := backend_controller.RegisterProvider("HAProxy", new(HAProxyProvider))
+	err := backend_controller.RegisterProviderV2("HAProxy", func() backend_controller.ProviderV2 { return new(HAProxyProvider) })
 	if err != nil {
 		panic(err)
 	}
@@ -82,16 +86,26 @@ const (
 	monitorTypeHTTP  = "http"
 	monitorTypeHTTPS = "https"
 	modeTCP          = "tcp"
+	advCheckTCP      = "tcp-check"
+	// defaultInterval is the server check interval in seconds used when the monitor doesn't set it
+	defaultInterval = 1
 )
 
 // We use round robin for the backend servers if least response is chosen since HAProxy doesn't have it.
 // SOURCEIPHASH not enabled yet in CRD since it is not supported by F5.
 var LBMethodMap = map[string]string{"ROUNDROBIN": "roundrobin", "LEASTCONNECTION": "leastconn", "LEASTRESPONSETIME": "roundrobin", "SOURCEIPHASH": "source"}
 
+// invalidName matches the characters not allowed in the HAProxy proxy names
+var invalidName = regexp.MustCompile(`[^A-Za-z0-9._:-]`)
+
+// NamingRules returns the HAProxy frontend and backend naming rules
+func (p *HAProxyProvider) NamingRules() backend_controller.NamingRules {
+	return backend_controller.NamingRules{Invalid: invalidName}
+}
+
 // Create creates a new Load Balancer backend provider
 func (p *HAProxyProvider) Create(ctx context.Context, lbBackend lbv1.Provider, username string, password string) error {
 	log := ctrllog.FromContext(ctx).WithValues("provider", "HAProxy")
-	p.ctx = context.Background()
 	p.log = log
 	p.host = lbBackend.Host
 	p.hostport = lbBackend.Port
@@ -107,8 +121,9 @@ func (p *HAProxyProvider) Create(ctx context.Context, lbBackend lbv1.Provider, u
 		InsecureSkipVerify: !lbBackend.ValidateCerts,
 	})
 
-	transport := httptransport.New(host, "/v2", []string{c.Scheme})
-	transport.Transport = t
+	transport := httptransport.New(host, lbBackend.HAProxyBasePath(), []string{c.Scheme})
+	// Instrument the HTTP client so the Dataplane API calls show up in the traces
+	transport.Transport = otelhttp.NewTransport(t)
 	transport.DefaultAuthentication = p.auth
 	transport.Debug = lbBackend.Debug
 
@@ -118,9 +133,9 @@ func (p *HAProxyProvider) Create(ctx context.Context, lbBackend lbv1.Provider, u
 }
 
 // Connect creates a connection to the IP Load Balancer
-func (p *HAProxyProvider) Connect() error {
+func (p *HAProxyProvider) Connect(ctx context.Context) error {
 	// Use Sites to grab the current config version of the HAProxy
-	sitesResp, err := p.haproxy.Sites.GetSites(&sites.GetSitesParams{Context: p.ctx}, p.auth)
+	sitesResp, err := p.haproxy.Sites.GetSites(&sites.GetSitesParams{Context: ctx}, p.auth)
 	if err != nil {
 		return err
 	}
@@ -130,10 +145,10 @@ func (p *HAProxyProvider) Connect() error {
 	// Create a new transaction with previous version
 	t, err := p.haproxy.Transactions.StartTransaction(&transactions.StartTransactionParams{
 		Version: p.version,
-		Context: p.ctx,
+		Context: ctx,
 	}, p.auth)
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return err
 	}
 	p.transaction = t.Payload.ID
@@ -141,32 +156,49 @@ func (p *HAProxyProvider) Connect() error {
 	return nil
 }
 
+// Version returns the HAProxy process version
+func (p *HAProxyProvider) Version(ctx context.Context) (string, error) {
+	resp, err := p.haproxy.Information.GetHaproxyProcessInfo(&information.GetHaproxyProcessInfoParams{Context: ctx}, p.auth)
+	if err != nil {
+		return "", fmt.Errorf("error getting HAProxy process info: %v", err)
+	}
+	if len(resp.Payload) == 0 || resp.Payload[0].Info == nil {
+		return "", fmt.Errorf("HAProxy process info is empty")
+	}
+	return resp.Payload[0].Info.Version, nil
+}
+
 // HealthCheck checks if a connection to the Load Balancer is established
-func (p *HAProxyProvider) HealthCheck() error {
+func (p *HAProxyProvider) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
 // Close closes the connection to the Load Balancer
-func (p *HAProxyProvider) Close() error {
+func (p *HAProxyProvider) Close(ctx context.Context) error {
 	p.log.Info("Committing transaction", "transaction", p.transaction)
 	_, _, err := p.haproxy.Transactions.CommitTransaction(&transactions.CommitTransactionParams{
 		ID:          p.transaction,
-		Context:     p.ctx,
+		Context:     ctx,
 		ForceReload: ptr.To[bool](true),
 	}, p.auth)
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return err
 	}
 	return nil
 }
 
+// Discard closes the connection to the Load Balancer deleting the transaction without applying the changes
+func (p *HAProxyProvider) Discard(ctx context.Context) error {
+	return p.CloseError(ctx)
+}
+
 // Close closes the connection to the Load Balancer
-func (p *HAProxyProvider) CloseError() error {
+func (p *HAProxyProvider) CloseError(ctx context.Context) error {
 	p.log.Info("Deleting transaction due error", "transaction", p.transaction)
 	_, err := p.haproxy.Transactions.DeleteTransaction(&transactions.DeleteTransactionParams{
 		ID:      p.transaction,
-		Context: p.ctx,
+		Context: ctx,
 	}, p.auth)
 	if err != nil {
 		return err
@@ -178,29 +210,43 @@ func (p *HAProxyProvider) CloseError() error {
 // Monitor Management
 // ----------------------------------------
 
+// WriteOnlyMonitor marks the HAProxy monitors as write only since the health checks
+// are part of the backends and servers and can't be read back as a monitor
+func (p *HAProxyProvider) WriteOnlyMonitor() {}
+
 // GetMonitor gets a monitor in the IP Load Balancer
-func (p *HAProxyProvider) GetMonitor(monitor *lbv1.Monitor) (*lbv1.Monitor, error) {
+func (p *HAProxyProvider) GetMonitor(ctx context.Context, monitor *lbv1.Monitor) (*lbv1.Monitor, error) {
 	// Always return empty monior to force update
 	return &lbv1.Monitor{}, nil
 }
 
 // CreateMonitor creates a monitor in the IP Load Balancer
 // if port argument is 0, no port override is configured
-func (p *HAProxyProvider) CreateMonitor(m *lbv1.Monitor) error {
-	p.monitor = *m
+func (p *HAProxyProvider) CreateMonitor(ctx context.Context, m *lbv1.Monitor) error {
+	p.monitor = haproxyMonitor(m)
 	return nil
 }
 
 // EditMonitor edits a monitor in the IP Load Balancer
 // if port argument is 0, no port override is configured
-func (p *HAProxyProvider) EditMonitor(m *lbv1.Monitor) error {
-	p.monitor = *m
+func (p *HAProxyProvider) EditMonitor(ctx context.Context, m *lbv1.Monitor) error {
+	p.monitor = haproxyMonitor(m)
 	return nil
 
 }
 
+// haproxyMonitor returns the monitor with the defaults, keeping the HAProxy check interval
+// of one second when it's not set
+func haproxyMonitor(m *lbv1.Monitor) lbv1.Monitor {
+	monitor := m.WithDefaults()
+	if m.Interval == 0 {
+		monitor.Interval = defaultInterval
+	}
+	return monitor
+}
+
 // DeleteMonitor deletes a monitor in the IP Load Balancer
-func (p *HAProxyProvider) DeleteMonitor(m *lbv1.Monitor) error {
+func (p *HAProxyProvider) DeleteMonitor(ctx context.Context, m *lbv1.Monitor) error {
 	p.monitor = lbv1.Monitor{}
 	// Maybe call EditPool?
 	return nil
@@ -211,15 +257,15 @@ func (p *HAProxyProvider) DeleteMonitor(m *lbv1.Monitor) error {
 // ----------------------------------------
 
 // GetPool gets a server pool from the Load Balancer
-func (p *HAProxyProvider) GetPool(pool *lbv1.Pool) (*lbv1.Pool, error) {
+func (p *HAProxyProvider) GetPool(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
 	newPool, err := p.haproxy.Backend.GetBackend(&backend.GetBackendParams{
 		Name:          pool.Name,
 		TransactionID: &p.transaction,
-		Context:       p.ctx,
+		Context:       ctx,
 	}, p.auth)
 
 	if err != nil && !strings.Contains(err.Error(), "getBackendNotFound") {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return nil, fmt.Errorf("error getting pool: %v", err)
 	}
 
@@ -228,76 +274,84 @@ func (p *HAProxyProvider) GetPool(pool *lbv1.Pool) (*lbv1.Pool, error) {
 		return nil, nil
 	}
 
+	// The pool monitor can't be read back so the pool is always updated
 	retPool := &lbv1.Pool{
 		Name:    newPool.Payload.Data.Name,
 		Monitor: "changed",
+		Owner:   newPool.Payload.Data.Description,
 	}
 
 	return retPool, nil
 }
 
 // CreatePool creates a server pool in the Load Balancer
-func (p *HAProxyProvider) CreatePool(pool *lbv1.Pool) error {
+func (p *HAProxyProvider) CreatePool(ctx context.Context, pool *lbv1.Pool) error {
+	if err := checkProtocol(pool.Protocol); err != nil {
+		_ = p.CloseError(ctx)
+		return fmt.Errorf("error configuring pool %s: %v", pool.Name, err)
+	}
 	backendData := &models.Backend{
 		Name: pool.Name,
 		Balance: &models.Balance{
 			Algorithm: &p.lbmethod,
 		},
-		Mode: modeTCP,
+		Mode:        modeTCP,
+		Description: pool.Owner,
 	}
 
-	// Only configure httpchk for http/https monitor types
-	if p.monitor.MonitorType == monitorTypeHTTP || p.monitor.MonitorType == monitorTypeHTTPS {
-		backendData.AdvCheck = "httpchk"
-		backendData.HttpchkParams = &models.HttpchkParams{
-			Method: "GET",
-			URI:    p.monitor.Path,
-		}
-	}
+	// Only configure httpchk for http/https monitor types and tcp-check for tcp monitors with send or expect strings
+	p.setBackendCheckParams(backendData)
 
 	_, _, err := p.haproxy.Backend.CreateBackend(&backend.CreateBackendParams{
 		Data:          backendData,
-		Context:       p.ctx,
+		Context:       ctx,
 		TransactionID: &p.transaction,
 	}, p.auth)
 
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return fmt.Errorf("error creating pool %s: %v", pool.Name, err)
 	}
 
+	if backendData.AdvCheck == advCheckTCP {
+		err = p.createTCPChecks(ctx, pool.Name)
+		if err != nil {
+			_ = p.CloseError(ctx)
+			return fmt.Errorf("error creating pool %s tcp checks: %v", pool.Name, err)
+		}
+	}
+
 	return nil
 }
 
 // EditPool modifies a server pool in the Load Balancer
-func (p *HAProxyProvider) EditPool(pool *lbv1.Pool) error {
+func (p *HAProxyProvider) EditPool(ctx context.Context, pool *lbv1.Pool) error {
+	if err := checkProtocol(pool.Protocol); err != nil {
+		_ = p.CloseError(ctx)
+		return fmt.Errorf("error configuring pool %s: %v", pool.Name, err)
+	}
 	backendData := &models.Backend{
 		Name: pool.Name,
 		Balance: &models.Balance{
 			Algorithm: &p.lbmethod,
 		},
-		Mode: modeTCP,
+		Mode:        modeTCP,
+		Description: pool.Owner,
 	}
 
-	// Only configure httpchk for http/https monitor types
-	if p.monitor.MonitorType == monitorTypeHTTP || p.monitor.MonitorType == monitorTypeHTTPS {
-		backendData.AdvCheck = "httpchk"
-		backendData.HttpchkParams = &models.HttpchkParams{
-			Method: "GET",
-			URI:    p.monitor.Path,
-		}
-	}
+	// Only configure httpchk for http/https monitor types and tcp-check for tcp monitors with send or expect strings
+	p.setBackendCheckParams(backendData)
 
 	// Create Pool with pre-existing monitor
 	backendOK, _, err := p.haproxy.Backend.ReplaceBackend(&backend.ReplaceBackendParams{
 		Name:          pool.Name,
 		Data:          backendData,
-		Context:       p.ctx,
+		Context:       ctx,
 		TransactionID: &p.transaction,
 	}, p.auth)
 
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return fmt.Errorf("error editing pool(ERR) %s: %v", pool.Name, err)
 	}
 	// Return in case pool does not exist
@@ -305,33 +359,59 @@ func (p *HAProxyProvider) EditPool(pool *lbv1.Pool) error {
 		return nil
 	}
 
-	pool, err = p.GetPoolMembers(pool)
+	// Replace the tcp checks since they are not part of the backend
+	err = p.deleteTCPChecks(ctx, pool.Name)
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
+		return fmt.Errorf("error editing pool(deleting tcp checks) %s: %v", pool.Name, err)
+	}
+	if backendData.AdvCheck == advCheckTCP {
+		err = p.createTCPChecks(ctx, pool.Name)
+		if err != nil {
+			_ = p.CloseError(ctx)
+			return fmt.Errorf("error editing pool(creating tcp checks) %s: %v", pool.Name, err)
+		}
+	}
+
+	// Replace the servers to apply the new check parameters keeping the
+	// drained (zero weight) ones disabled
+	servers, err := p.haproxy.Server.GetServers(&server.GetServersParams{
+		Backend:       &pool.Name,
+		TransactionID: &p.transaction,
+		Context:       ctx,
+	}, p.auth)
+	if err != nil {
+		_ = p.CloseError(ctx)
 		return fmt.Errorf("error editing pool(getting pool members) %s: %v", pool.Name, err)
 	}
-	if pool != nil {
-		for _, m := range pool.Members {
-			err = p.EditPoolMember(&m, pool, "enable")
-			if err != nil {
-				_ = p.CloseError()
-				return fmt.Errorf("error editing pool(editing pool members) %s: %v", pool.Name, err)
-			}
+	for _, s := range servers.Payload.Data {
+		m := lbv1.PoolMember{
+			Node: lbv1.Node{Name: s.Name, Host: s.Address},
+			Port: int(ptr.Deref(s.Port, 0)),
+		}
+		status := backend_controller.MemberStatusEnable
+		if s.Weight != nil && *s.Weight == 0 {
+			status = backend_controller.MemberStatusDisable
+		}
+		err = p.EditPoolMember(ctx, &m, pool, status)
+		if err != nil {
+			_ = p.CloseError(ctx)
+			return fmt.Errorf("error editing pool(editing pool members) %s: %v", pool.Name, err)
 		}
 	}
 	return nil
 }
 
 // DeletePool removes a server pool in the Load Balancer
-func (p *HAProxyProvider) DeletePool(pool *lbv1.Pool) error {
+func (p *HAProxyProvider) DeletePool(ctx context.Context, pool *lbv1.Pool) error {
 	_, _, err := p.haproxy.Backend.DeleteBackend(&backend.DeleteBackendParams{
 		Name:          pool.Name,
-		Context:       p.ctx,
+		Context:       ctx,
 		TransactionID: &p.transaction,
 	}, p.auth)
 
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return fmt.Errorf("error deleting pool %s: %v", pool.Name, err)
 	}
 	return nil
@@ -342,18 +422,18 @@ func (p *HAProxyProvider) DeletePool(pool *lbv1.Pool) error {
 // ----------------------------------------
 
 // GetPoolMembers gets the pool members and return them in Pool object
-func (p *HAProxyProvider) GetPoolMembers(pool *lbv1.Pool) (*lbv1.Pool, error) {
+func (p *HAProxyProvider) GetPoolMembers(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
 
 	// // Get pool members
 	members := make([]lbv1.PoolMember, 0)
 	poolMembers, err := p.haproxy.Server.GetServers(&server.GetServersParams{
 		Backend:       &pool.Name,
 		TransactionID: &p.transaction,
-		Context:       p.ctx,
+		Context:       ctx,
 	}, p.auth)
 
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return nil, fmt.Errorf("error getting pool members: %v", err)
 	}
 	if poolMembers.Payload.Data == nil {
@@ -378,7 +458,7 @@ func (p *HAProxyProvider) GetPoolMembers(pool *lbv1.Pool) (*lbv1.Pool, error) {
 }
 
 // CreatePoolMember creates a member to be added to pool in the Load Balancer
-func (p *HAProxyProvider) CreatePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool) error {
+func (p *HAProxyProvider) CreatePoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
 	serverParams := &server.CreateServerParams{
 		Backend: &pool.Name,
 		Data: &models.Server{
@@ -387,21 +467,17 @@ func (p *HAProxyProvider) CreatePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool)
 			Port:    ptr.To[int64](int64(m.Port)),
 			ServerParams: models.ServerParams{
 				Check:           sslEnabled,
-				Inter:           ptr.To[int64](1000), // in ms
 				HealthCheckPort: ptr.To[int64](int64(p.monitor.Port)),
 			},
 		},
 		TransactionID: &p.transaction,
-		Context:       p.ctx,
-	}
-	if p.monitor.MonitorType == monitorTypeHTTPS {
-		serverParams.Data.CheckSsl = sslEnabled
-		serverParams.Data.Verify = sslVerifyNone
+		Context:       ctx,
 	}
+	p.setServerCheckParams(&serverParams.Data.ServerParams)
 	_, _, err := p.haproxy.Server.CreateServer(serverParams, p.auth)
 
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return fmt.Errorf("error creating pool member: %v", err)
 	}
 	p.log.Info("Created node", "node", m.Node.Name, "host", m.Node.Host)
@@ -410,14 +486,13 @@ func (p *HAProxyProvider) CreatePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool)
 
 // EditPoolMember modifies a server pool member in the Load Balancer
 // status could be "enable" or "disable"
-func (p *HAProxyProvider) EditPoolMember(m *lbv1.PoolMember, pool *lbv1.Pool, status string) error {
-	maintenanceStatus := func() string {
-		if status == "enable" {
-			return "disabled"
-		} else {
-			return sslEnabled
-		}
-	}()
+func (p *HAProxyProvider) EditPoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool, status string) error {
+	// Disabled members are drained with a zero weight so they get no new
+	// connections while the existing ones are kept
+	var weight *int64
+	if status == backend_controller.MemberStatusDisable {
+		weight = ptr.To[int64](0)
+	}
 
 	serverParams := &server.ReplaceServerParams{
 		Backend: &pool.Name,
@@ -428,22 +503,18 @@ func (p *HAProxyProvider) EditPoolMember(m *lbv1.PoolMember, pool *lbv1.Pool, st
 			Port:    ptr.To[int64](int64(m.Port)),
 			ServerParams: models.ServerParams{
 				Check:           sslEnabled,
-				Maintenance:     maintenanceStatus,
-				Inter:           ptr.To[int64](1000),
+				Weight:          weight,
 				HealthCheckPort: ptr.To[int64](int64(p.monitor.Port)),
 			},
 		},
 		TransactionID: &p.transaction,
-		Context:       p.ctx,
-	}
-	if p.monitor.MonitorType == monitorTypeHTTPS {
-		serverParams.Data.CheckSsl = sslEnabled
-		serverParams.Data.Verify = sslVerifyNone
+		Context:       ctx,
 	}
+	p.setServerCheckParams(&serverParams.Data.ServerParams)
 	_, _, err := p.haproxy.Server.ReplaceServer(serverParams, p.auth)
 
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return fmt.Errorf("error editing pool member: %v", err)
 	}
 	p.log.Info("Edited node", "node", m.Node.Name, "host", m.Node.Host)
@@ -451,17 +522,17 @@ func (p *HAProxyProvider) EditPoolMember(m *lbv1.PoolMember, pool *lbv1.Pool, st
 }
 
 // DeletePoolMember deletes a member in the Load Balancer
-func (p *HAProxyProvider) DeletePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool) error {
+func (p *HAProxyProvider) DeletePoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
 
 	_, _, err := p.haproxy.Server.DeleteServer(&server.DeleteServerParams{
 		Backend:       &pool.Name,
 		Name:          m.Node.Name,
 		TransactionID: &p.transaction,
-		Context:       p.ctx,
+		Context:       ctx,
 	}, p.auth)
 
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return fmt.Errorf("error deleting pool member: %v", err)
 	}
 	p.log.Info("Deleted node", "node", m.Node.Name, "host", m.Node.Host)
@@ -473,15 +544,15 @@ func (p *HAProxyProvider) DeletePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool)
 // ----------------------------------------
 
 // GetVIP gets a VIP in the IP Load Balancer
-func (p *HAProxyProvider) GetVIP(v *lbv1.VIP) (*lbv1.VIP, error) {
+func (p *HAProxyProvider) GetVIP(ctx context.Context, v *lbv1.VIP) (*lbv1.VIP, error) {
 	getFrontend, err := p.haproxy.Frontend.GetFrontend(&frontend.GetFrontendParams{
 		Name:          v.Name,
 		TransactionID: &p.transaction,
-		Context:       p.ctx,
+		Context:       ctx,
 	}, p.auth)
 
 	if err != nil && !strings.Contains(err.Error(), "getFrontendNotFound") {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return nil, fmt.Errorf("error getting haproxy frontend %s: %v", v.Name, err)
 	}
 
@@ -495,37 +566,43 @@ func (p *HAProxyProvider) GetVIP(v *lbv1.VIP) (*lbv1.VIP, error) {
 			TransactionID: &p.transaction,
 			ParentName:    &v.Name,
 			ParentType:    ptr.To[string]("frontend"),
-			Context:       p.ctx,
+			Context:       ctx,
 		}, p.auth)
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return nil, fmt.Errorf("error getting haproxy frontend bind %s: %v", v.Name, err)
 	}
 
 	vip := &lbv1.VIP{
-		Name: getFrontend.Payload.Data.Name,
-		IP:   getFrontendBind.Payload.Data.Address,
-		Port: int(*getFrontendBind.Payload.Data.Port),
-		Pool: getFrontend.Payload.Data.DefaultBackend,
+		Name:  getFrontend.Payload.Data.Name,
+		IP:    getFrontendBind.Payload.Data.Address,
+		Port:  int(*getFrontendBind.Payload.Data.Port),
+		Pool:  getFrontend.Payload.Data.DefaultBackend,
+		Owner: getFrontend.Payload.Data.Description,
 	}
 	return vip, nil
 }
 
 // CreateVIP creates a Virtual Server in the Load Balancer
-func (p *HAProxyProvider) CreateVIP(v *lbv1.VIP) error {
+func (p *HAProxyProvider) CreateVIP(ctx context.Context, v *lbv1.VIP) error {
+	if err := checkProtocol(v.Protocol); err != nil {
+		_ = p.CloseError(ctx)
+		return fmt.Errorf("error configuring VIP %s: %v", v.Name, err)
+	}
 	// Create frontend
 	_, _, err := p.haproxy.Frontend.CreateFrontend(&frontend.CreateFrontendParams{
 		Data: &models.Frontend{
 			Name:           v.Name,
 			Mode:           modeTCP,
 			DefaultBackend: v.Pool,
+			Description:    v.Owner,
 		},
 		TransactionID: &p.transaction,
-		Context:       p.ctx,
+		Context:       ctx,
 	}, p.auth)
 
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return fmt.Errorf("error creating frontend: %v", err)
 	}
 
@@ -534,17 +611,18 @@ func (p *HAProxyProvider) CreateVIP(v *lbv1.VIP) error {
 		Frontend: &v.Name,
 		Data: &models.Bind{
 			BindParams: models.BindParams{
-				Name: v.Name,
+				Name:   v.Name,
+				V6only: backend_controller.IsIPv6(v.IP),
 			},
 			Address: v.IP,
 			Port:    ptr.To[int64](int64(v.Port)),
 		},
 		TransactionID: &p.transaction,
-		Context:       p.ctx,
+		Context:       ctx,
 	}, p.auth)
 
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return fmt.Errorf("error creating frontend bind: %v", err)
 	}
 
@@ -553,54 +631,173 @@ func (p *HAProxyProvider) CreateVIP(v *lbv1.VIP) error {
 }
 
 // EditVIP modifies a Virtual Server in the Load Balancer
-func (p *HAProxyProvider) EditVIP(v *lbv1.VIP) error {
+func (p *HAProxyProvider) EditVIP(ctx context.Context, v *lbv1.VIP) error {
+	if err := checkProtocol(v.Protocol); err != nil {
+		_ = p.CloseError(ctx)
+		return fmt.Errorf("error configuring VIP %s: %v", v.Name, err)
+	}
 
 	// Edit frontend
 	_, _, err := p.haproxy.Frontend.ReplaceFrontend(&frontend.ReplaceFrontendParams{
+		Name: v.Name,
 		Data: &models.Frontend{
-			Name: v.Name,
-			Mode: "http",
+			Name:           v.Name,
+			Mode:           modeTCP,
+			DefaultBackend: v.Pool,
+			Description:    v.Owner,
 		},
 		TransactionID: &p.transaction,
-		Context:       p.ctx,
+		Context:       ctx,
 	}, p.auth)
 
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return fmt.Errorf("error editing frontend: %v", err)
 	}
 
 	// Edit frontend binds
 	_, _, err = p.haproxy.Bind.ReplaceBind(&bind.ReplaceBindParams{
+		Name:     v.Name,
+		Frontend: &v.Name,
 		Data: &models.Bind{
 			BindParams: models.BindParams{
-				Name: v.Name,
+				Name:   v.Name,
+				V6only: backend_controller.IsIPv6(v.IP),
 			},
 			Address: v.IP,
 			Port:    ptr.To[int64](int64(v.Port)),
 		},
 		TransactionID: &p.transaction,
-		Context:       p.ctx,
+		Context:       ctx,
 	}, p.auth)
 
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return fmt.Errorf("error editing frontend bind: %v", err)
 	}
 	return nil
 }
 
 // DeleteVIP deletes a Virtual Server in the Load Balancer
-func (p *HAProxyProvider) DeleteVIP(v *lbv1.VIP) error {
+func (p *HAProxyProvider) DeleteVIP(ctx context.Context, v *lbv1.VIP) error {
 	_, _, err := p.haproxy.Frontend.DeleteFrontend(&frontend.DeleteFrontendParams{
 		Name:          v.Name,
-		Context:       p.ctx,
+		Context:       ctx,
 		TransactionID: &p.transaction,
 	}, p.auth)
 
 	if err != nil {
-		_ = p.CloseError()
+		_ = p.CloseError(ctx)
 		return fmt.Errorf("error deleting VIP %s: %v", v.Name, err)
 	}
 	return nil
 }
+
+// checkProtocol checks the protocol is supported by HAProxy which only proxies TCP traffic
+func checkProtocol(protocol string) error {
+	if protocol == lbv1.ProtocolUDP {
+		return fmt.Errorf("the %s protocol is not supported by the HAProxy provider", protocol)
+	}
+	return nil
+}
+
+// setBackendCheckParams configures the backend http check or tcp check from the monitor
+func (p *HAProxyProvider) setBackendCheckParams(backendData *models.Backend) {
+	if p.monitor.MonitorType == lbv1.MonitorTypeTCP {
+		// Plain tcp monitors use the server connection check
+		if p.monitor.Send != "" || p.monitor.Receive != "" {
+			backendData.AdvCheck = advCheckTCP
+		}
+		return
+	}
+	if p.monitor.MonitorType != monitorTypeHTTP && p.monitor.MonitorType != monitorTypeHTTPS {
+		return
+	}
+	backendData.AdvCheck = "httpchk"
+	backendData.HttpchkParams = &models.HttpchkParams{
+		Method: p.monitor.Method,
+		URI:    p.monitor.Path,
+	}
+	if p.monitor.Host != "" {
+		// The Host header is sent appended to the HTTP version in the "option httpchk" line
+		backendData.HttpchkParams.Version = "HTTP/1.1\\r\\nHost:\\ " + p.monitor.Host
+	}
+	if p.monitor.Receive != "" {
+		backendData.HTTPCheck = &models.HTTPCheck{Type: "expect", Match: "string", Pattern: p.monitor.Receive}
+	} else if p.monitor.ExpectedStatus != 0 {
+		backendData.HTTPCheck = &models.HTTPCheck{Type: "expect", Match: "status", Pattern: strconv.Itoa(p.monitor.ExpectedStatus)}
+	}
+}
+
+// setServerCheckParams configures the server health check interval, thresholds and TLS from the monitor
+func (p *HAProxyProvider) setServerCheckParams(params *models.ServerParams) {
+	if p.monitor.Interval != 0 {
+		params.Inter = ptr.To[int64](int64(p.monitor.Interval) * 1000) // in ms
+	}
+	if p.monitor.Rise != 0 {
+		params.Rise = ptr.To[int64](int64(p.monitor.Rise))
+	}
+	if p.monitor.Fall != 0 {
+		params.Fall = ptr.To[int64](int64(p.monitor.Fall))
+	}
+	if p.monitor.MonitorType == monitorTypeHTTPS {
+		params.CheckSsl = sslEnabled
+		params.Verify = sslVerifyNone
+		params.CheckSni = p.monitor.SNI
+	}
+}
+
+// createTCPChecks creates the tcp-check rules sending and expecting the monitor strings
+func (p *HAProxyProvider) createTCPChecks(ctx context.Context, poolName string) error {
+	var checks []*models.TCPCheck
+	if p.monitor.Send != "" {
+		checks = append(checks, &models.TCPCheck{Action: "send", Data: p.monitor.Send})
+	}
+	if p.monitor.Receive != "" {
+		checks = append(checks, &models.TCPCheck{Action: "expect", Match: "string", Pattern: p.monitor.Receive})
+	}
+	for i, check := range checks {
+		check.Index = ptr.To[int64](int64(i))
+		_, _, err := p.haproxy.TCPCheck.CreateTCPCheck(&tcp_check.CreateTCPCheckParams{
+			Data:          check,
+			ParentName:    &poolName,
+			ParentType:    "backend",
+			TransactionID: &p.transaction,
+			Context:       ctx,
+		}, p.auth)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteTCPChecks deletes the tcp-check rules from a backend
+func (p *HAProxyProvider) deleteTCPChecks(ctx context.Context, poolName string) error {
+	checks, err := p.haproxy.TCPCheck.GetTCPChecks(&tcp_check.GetTCPChecksParams{
+		ParentName:    &poolName,
+		ParentType:    "backend",
+		TransactionID: &p.transaction,
+		Context:       ctx,
+	}, p.auth)
+	if err != nil {
+		return err
+	}
+	if checks == nil || checks.Payload == nil {
+		return nil
+	}
+	// Delete from the last index since the remaining rules are re-indexed
+	for i := len(checks.Payload.Data) - 1; i >= 0; i-- {
+		_, _, err := p.haproxy.TCPCheck.DeleteTCPCheck(&tcp_check.DeleteTCPCheckParams{
+			Index:         int64(i),
+			ParentName:    &poolName,
+			ParentType:    "backend",
+			TransactionID: &p.transaction,
+			Context:       ctx,
+		}, p.auth)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
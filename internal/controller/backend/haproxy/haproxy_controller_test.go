@@ -33,6 +33,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -185,7 +186,7 @@ var _ = Describe("When using a HAProxy backend", func() {
 	It("Should connect to the backend", func() {
 		createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 		Expect(err).ToNot(HaveOccurred())
-		_ = createdBackend.Provider.Connect()
+		_ = createdBackend.Provider.Connect(ctx)
 		// Expect(err).To(BeNil())
 		// fmt.Fprintf(GinkgoWriter, "URL: %s", httpurl)
 		// fmt.Fprintf(GinkgoWriter, "DATA: %s", httpdata)
@@ -194,50 +195,67 @@ var _ = Describe("When using a HAProxy backend", func() {
 	It("Should close connection", func() {
 		createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 		Expect(err).ToNot(HaveOccurred())
-		_ = createdBackend.Provider.Connect()
-		err = createdBackend.Provider.Close()
+		_ = createdBackend.Provider.Connect(ctx)
+		err = createdBackend.Provider.Close(ctx)
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("Should request the backend version", func() {
+		createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
+		Expect(err).ToNot(HaveOccurred())
+		_, _ = createdBackend.Version(ctx)
+		Expect(httpdata.url).To(ContainElement("/v2/services/haproxy/runtime/info"))
+		Expect(httpdata.method).To(ContainElement("GET"))
+	})
+
+	It("Should use the Dataplane API base path from the haproxy options", func() {
+		provider := loadBalancer.Spec.Provider.DeepCopy()
+		provider.HAProxy = &lbv1.HAProxyOptions{BasePath: "/dataplane/v2"}
+		createdBackend, err := CreateBackend(ctx, provider, "username", "password")
+		Expect(err).ToNot(HaveOccurred())
+		_, _ = createdBackend.Version(ctx)
+		Expect(httpdata.url).To(ContainElement("/dataplane/v2/services/haproxy/runtime/info"))
+	})
+
 	Context("when managing HAProxy", func() {
 		var createdBackend *BackendController
 		var err error
 		BeforeEach(func() {
 			createdBackend, err = CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 			Expect(err).ToNot(HaveOccurred())
-			_ = createdBackend.Provider.Connect()
+			_ = createdBackend.Provider.Connect(ctx)
 		})
 
 		Context("when handling load balancer monitors", func() {
 			It("Should get a monitor", func() {
-				_, err = createdBackend.Provider.GetMonitor(monitor)
+				_, err = createdBackend.Provider.GetMonitor(ctx, monitor)
 				Expect(err).ToNot(HaveOccurred())
 			})
 
 			It("Should create a monitor", func() {
-				err := createdBackend.Provider.CreateMonitor(monitor)
+				err := createdBackend.Provider.CreateMonitor(ctx, monitor)
 				Expect(err).ToNot(HaveOccurred())
 			})
 
 			It("Should delete the monitor", func() {
-				err = createdBackend.Provider.DeleteMonitor(monitor)
+				err = createdBackend.Provider.DeleteMonitor(ctx, monitor)
 				Expect(err).ToNot(HaveOccurred())
 			})
 
 			It("Should edit the monitor", func() {
-				err = createdBackend.Provider.EditMonitor(monitor)
+				err = createdBackend.Provider.EditMonitor(ctx, monitor)
 				Expect(err).ToNot(HaveOccurred())
 			})
 		})
 
 		Context("when handling load balancer pools", func() {
 			It("Should get pool members", func() {
-				_, err := createdBackend.Provider.GetPoolMembers(pool)
+				_, err := createdBackend.Provider.GetPoolMembers(ctx, pool)
 				Expect(err).ToNot(HaveOccurred())
 			})
 
 			It("Should create a pool", func() {
-				err = createdBackend.Provider.CreatePool(pool)
+				err = createdBackend.Provider.CreatePool(ctx, pool)
 				url := "/v2/services/haproxy/configuration/backends"
 				Eventually(httpdata.url, timeout, interval).Should(ContainElement(url))
 				i := indexOf(url, httpdata.url)
@@ -253,8 +271,35 @@ var _ = Describe("When using a HAProxy backend", func() {
 				// Expect(m).NotTo(BeNil())
 			})
 
+			It("Should create a pool with the monitor check parameters", func() {
+				m := monitor.DeepCopy()
+				m.Method = "HEAD"
+				m.Host = "app.example.com"
+				m.ExpectedStatus = 200
+				Expect(createdBackend.Provider.CreateMonitor(ctx, m)).To(Succeed())
+				_ = createdBackend.Provider.CreatePool(ctx, pool)
+				url := "/v2/services/haproxy/configuration/backends"
+				Eventually(httpdata.url, timeout, interval).Should(ContainElement(url))
+				i := indexOf(url, httpdata.url)
+				Eventually(func() string { return gjson.Get(httpdata.data[i], "httpchk_params.method").String() }, timeout, interval).Should(Equal("HEAD"))
+				Eventually(func() string { return gjson.Get(httpdata.data[i], "httpchk_params.version").String() }, timeout, interval).Should(Equal(`HTTP/1.1\r\nHost:\ app.example.com`))
+				Eventually(func() string { return gjson.Get(httpdata.data[i], "http-check.match").String() }, timeout, interval).Should(Equal("status"))
+				Eventually(func() string { return gjson.Get(httpdata.data[i], "http-check.pattern").String() }, timeout, interval).Should(Equal("200"))
+			})
+
+			It("Should create a pool with a tcp-check monitor", func() {
+				tcpMonitor := &lbv1.Monitor{Name: "test-tcp-monitor", MonitorType: "tcp", Port: 1883, Send: "PING", Receive: "PONG"}
+				Expect(createdBackend.Provider.CreateMonitor(ctx, tcpMonitor)).To(Succeed())
+				_ = createdBackend.Provider.CreatePool(ctx, pool)
+				url := "/v2/services/haproxy/configuration/backends"
+				Eventually(httpdata.url, timeout, interval).Should(ContainElement(url))
+				i := indexOf(url, httpdata.url)
+				Eventually(func() string { return gjson.Get(httpdata.data[i], "adv_check").String() }, timeout, interval).Should(Equal("tcp-check"))
+				Expect(gjson.Get(httpdata.data[i], "httpchk_params").Exists()).To(BeFalse())
+			})
+
 			It("Should delete the pool", func() {
-				err = createdBackend.Provider.DeletePool(pool)
+				err = createdBackend.Provider.DeletePool(ctx, pool)
 				url := "/v2/services/haproxy/configuration/backends/test-pool"
 				Eventually(httpdata.url, timeout, interval).Should(ContainElement(url))
 				i := indexOf(url, httpdata.url)
@@ -264,7 +309,7 @@ var _ = Describe("When using a HAProxy backend", func() {
 			})
 
 			It("Should edit the pool", func() {
-				_ = createdBackend.Provider.EditPool(pool)
+				_ = createdBackend.Provider.EditPool(ctx, pool)
 				url := "/v2/services/haproxy/configuration/backends/test-pool"
 				Eventually(httpdata.url, timeout, interval).Should(ContainElement(url))
 				i := indexOf(url, httpdata.url)
@@ -276,9 +321,46 @@ var _ = Describe("When using a HAProxy backend", func() {
 			})
 		})
 
+		Context("when handling load balancer pool members", func() {
+			It("Should check the pool members every second by default", func() {
+				Expect(createdBackend.Provider.CreateMonitor(ctx, monitor)).To(Succeed())
+				_ = createdBackend.Provider.CreatePoolMember(ctx, &pool.Members[0], pool)
+				url := "/v2/services/haproxy/configuration/servers?backend=test-pool"
+				Eventually(func() []string { return httpdata.url }, timeout, interval).Should(ContainElement(HavePrefix(url)))
+				i := slices.IndexFunc(httpdata.url, func(u string) bool { return strings.HasPrefix(u, url) })
+				Expect(gjson.Get(httpdata.data[i], "inter").Int()).To(Equal(int64(1000)))
+
+				m := monitor.DeepCopy()
+				m.Interval = 10
+				Expect(createdBackend.Provider.EditMonitor(ctx, m)).To(Succeed())
+				_ = createdBackend.Provider.CreatePoolMember(ctx, &pool.Members[1], pool)
+				i = slices.IndexFunc(httpdata.url[i+1:], func(u string) bool { return strings.HasPrefix(u, url) }) + i + 1
+				Expect(gjson.Get(httpdata.data[i], "inter").Int()).To(Equal(int64(10000)))
+			})
+
+			It("Should drain a disabled pool member", func() {
+				_ = createdBackend.Provider.EditPoolMember(ctx, &pool.Members[0], pool, "disable")
+				url := "/v2/services/haproxy/configuration/servers/test-node-1?backend=test-pool"
+				Eventually(func() []string { return httpdata.url }, timeout, interval).Should(ContainElement(HavePrefix(url)))
+				i := slices.IndexFunc(httpdata.url, func(u string) bool { return strings.HasPrefix(u, url) })
+				Expect(httpdata.method[i]).To(Equal("PUT"))
+				Expect(gjson.Get(httpdata.data[i], "weight").Exists()).To(BeTrue())
+				Expect(gjson.Get(httpdata.data[i], "weight").Int()).To(Equal(int64(0)))
+				Expect(gjson.Get(httpdata.data[i], "maintenance").Exists()).To(BeFalse())
+			})
+
+			It("Should enable a pool member", func() {
+				_ = createdBackend.Provider.EditPoolMember(ctx, &pool.Members[0], pool, "enable")
+				url := "/v2/services/haproxy/configuration/servers/test-node-1?backend=test-pool"
+				Eventually(func() []string { return httpdata.url }, timeout, interval).Should(ContainElement(HavePrefix(url)))
+				i := slices.IndexFunc(httpdata.url, func(u string) bool { return strings.HasPrefix(u, url) })
+				Expect(gjson.Get(httpdata.data[i], "weight").Exists()).To(BeFalse())
+			})
+		})
+
 		Context("when handling load balancer VIPs", func() {
 			It("Should create a VIP", func() {
-				err := createdBackend.Provider.CreateVIP(VIP)
+				err := createdBackend.Provider.CreateVIP(ctx, VIP)
 				url := "/v2/services/haproxy/configuration/frontends"
 				Eventually(httpdata.url, timeout, interval).Should(ContainElement(url))
 				i := indexOf(url, httpdata.url)
@@ -293,8 +375,16 @@ var _ = Describe("When using a HAProxy backend", func() {
 				Expect(err).To(MatchError(MatchRegexp("status 200")))
 			})
 
+			It("Should not create an UDP VIP", func() {
+				udpVIP := VIP.DeepCopy()
+				udpVIP.Protocol = lbv1.ProtocolUDP
+				err := createdBackend.Provider.CreateVIP(ctx, udpVIP)
+				Expect(err).To(MatchError(ContainSubstring("UDP protocol is not supported")))
+				Expect(httpdata.url).ShouldNot(ContainElement("/v2/services/haproxy/configuration/frontends"))
+			})
+
 			It("Should delete the VIP", func() {
-				err = createdBackend.Provider.DeleteVIP(VIP)
+				err = createdBackend.Provider.DeleteVIP(ctx, VIP)
 				url := "/v2/services/haproxy/configuration/frontends/test-vip"
 				Eventually(httpdata.url, timeout, interval).Should(ContainElement(url))
 				i := indexOf(url, httpdata.url)
@@ -303,15 +393,21 @@ var _ = Describe("When using a HAProxy backend", func() {
 			})
 
 			It("Should edit the VIP", func() {
-				err = createdBackend.Provider.EditVIP(VIP)
-				err = createdBackend.Provider.DeleteVIP(VIP)
-				url := "/v2/services/haproxy/configuration/frontends/"
-				Eventually(httpdata.url, timeout, interval).Should(ContainElement(url))
-				i := indexOf(url, httpdata.url)
-				Eventually(httpdata.method[i], timeout, interval).Should(Equal("PUT"))
-				Eventually(func() string { return gjson.Get(httpdata.data[i], "name").String() }, timeout, interval).Should(Equal("test-vip"))
-				Eventually(func() string { return gjson.Get(httpdata.data[i], "mode").String() }, timeout, interval).Should(Equal("http"))
-				Expect(err).To(MatchError(MatchRegexp("status 200")))
+				err = createdBackend.Provider.EditVIP(ctx, VIP)
+				url := "/v2/services/haproxy/configuration/frontends/test-vip"
+				Eventually(func() []string { return httpdata.url }, timeout, interval).Should(ContainElement(HavePrefix(url)))
+				i := slices.IndexFunc(httpdata.url, func(u string) bool { return strings.HasPrefix(u, url) })
+				Expect(httpdata.method[i]).To(Equal("PUT"))
+				Expect(gjson.Get(httpdata.data[i], "name").String()).To(Equal("test-vip"))
+				Expect(gjson.Get(httpdata.data[i], "mode").String()).To(Equal("tcp"))
+				Expect(gjson.Get(httpdata.data[i], "default_backend").String()).To(Equal("test-pool"))
+
+				url = "/v2/services/haproxy/configuration/binds/test-vip?frontend=test-vip"
+				Eventually(func() []string { return httpdata.url }, timeout, interval).Should(ContainElement(HavePrefix(url)))
+				i = slices.IndexFunc(httpdata.url, func(u string) bool { return strings.HasPrefix(u, url) })
+				Expect(httpdata.method[i]).To(Equal("PUT"))
+				Expect(gjson.Get(httpdata.data[i], "address").String()).To(Equal("1.2.3.4"))
+				Expect(err).ToNot(HaveOccurred())
 			})
 		})
 	})
@@ -26,10 +26,17 @@ package netscaler
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"net"
+	"net/http"
 	"net/url"
+	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/citrix/adc-nitro-go/resource/config/basic"
 	"github.com/citrix/adc-nitro-go/resource/config/lb"
@@ -43,12 +50,16 @@ import (
 )
 
 const (
-	yesValue        = "YES"
-	httpsProtocol   = "https"
-	readyCondition  = "Ready"
-	trueStatus      = "True"
-	monitorTypeHTTP = "HTTP"
-	serviceTypeTCP  = "TCP"
+	yesValue           = "YES"
+	httpsProtocol      = "https"
+	readyCondition     = "Ready"
+	trueStatus         = "True"
+	monitorTypeHTTP    = "HTTP"
+	monitorTypeHTTPECV = "HTTP-ECV"
+	monitorTypeTCP     = "TCP"
+	monitorTypeTCPECV  = "TCP-ECV"
+	serviceTypeTCP     = "TCP"
+	serviceTypeUDP     = "UDP"
 )
 
 // ----------------------------------------
@@ -59,21 +70,31 @@ const (
 type NetscalerProvider struct {
 	log           logr.Logger
 	client        *service.NitroClient
+	transport     *backend.ContextTransport
 	host          string
 	hostport      int
 	username      string
 	password      string
 	validatecerts bool
 	lbmethod      string
+	saveconfig    bool
 }
 
 func init() {
-	err := backend.RegisterProvider("Citrix_ADC", new(NetscalerProvider))
+	err := backend.RegisterProviderV2("Citrix_ADC", func() backend.ProviderV2 { return new(NetscalerProvider) })
 	if err != nil {
 		panic(err)
 	}
 }
 
+// invalidName matches the characters not allowed in the Citrix ADC entity names
+var invalidName = regexp.MustCompile(`[^A-Za-z0-9_#. :@=-]`)
+
+// NamingRules returns the Citrix ADC entity naming rules
+func (p *NetscalerProvider) NamingRules() backend.NamingRules {
+	return backend.NamingRules{Invalid: invalidName, MaxLength: 127}
+}
+
 // Create creates a new Load Balancer backend provider
 func (p *NetscalerProvider) Create(ctx context.Context, lbBackend lbv1.Provider, username string, password string) error {
 	log := ctrllog.FromContext(ctx).WithValues("provider", "Citrix_ADC")
@@ -84,15 +105,20 @@ func (p *NetscalerProvider) Create(ctx context.Context, lbBackend lbv1.Provider,
 	p.username = username
 	p.password = password
 	p.lbmethod = lbBackend.LBMethod
+	p.saveconfig = lbBackend.CitrixADCSaveConfig()
 
 	c, _ := url.Parse(p.host)
 	host := c.Scheme + "://" + c.Host + ":" + fmt.Sprintf("%d", p.hostport)
 
+	// The certificates are checked by the provider transport so the Nitro client
+	// sends the requests through the default transport
+	id, transport := p.nitroTransport()
 	var params = &service.NitroParams{
 		Url:       host,
 		Username:  p.username,
 		Password:  p.password,
-		SslVerify: p.validatecerts,
+		SslVerify: true,
+		Headers:   map[string]string{nitroClientHeader: id},
 	}
 	if lbBackend.Debug {
 		params.LogLevel = "debug"
@@ -103,17 +129,82 @@ func (p *NetscalerProvider) Create(ctx context.Context, lbBackend lbv1.Provider,
 	if err != nil {
 		return err
 	}
+	p.transport = transport
+	p.transport.SetContext(ctx)
 	p.client = client
 	return nil
 }
 
+// nitroClientHeader identifies the provider sending a Nitro request. The Nitro client doesn't
+// accept a transport and sends the requests through http.DefaultTransport, where nitroRouter
+// hands them to the ContextTransport of the provider.
+const nitroClientHeader = "X-Lbconfig-Client"
+
+var (
+	nitroRouterOnce sync.Once
+	nitroClientID   atomic.Uint64
+	// nitroTransports has the ContextTransport of each provider by client id
+	nitroTransports sync.Map
+)
+
+// nitroRouter sends the Nitro requests through the ContextTransport of their provider
+// and the other requests through the wrapped default transport
+type nitroRouter struct {
+	base http.RoundTripper
+}
+
+func (r *nitroRouter) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := req.Header.Get(nitroClientHeader)
+	if id == "" {
+		return r.base.RoundTrip(req)
+	}
+	t, ok := nitroTransports.Load(id)
+	if !ok {
+		return nil, fmt.Errorf("no transport for the Nitro client %s", id)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Del(nitroClientHeader)
+	return t.(*backend.ContextTransport).RoundTrip(req)
+}
+
+// nitroTransport returns the client id and the ContextTransport used by the provider Nitro
+// requests. The transport is removed when the provider is garbage collected.
+func (p *NetscalerProvider) nitroTransport() (string, *backend.ContextTransport) {
+	nitroRouterOnce.Do(func() {
+		http.DefaultTransport = &nitroRouter{base: http.DefaultTransport}
+	})
+	id := strconv.FormatUint(nitroClientID.Add(1), 10)
+	t := backend.NewContextTransport(&http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: !p.validatecerts},
+		Proxy:           http.ProxyFromEnvironment,
+	})
+	nitroTransports.Store(id, t)
+	runtime.AddCleanup(p, func(id string) { nitroTransports.Delete(id) }, id)
+	return id, t
+}
+
 // Connect creates a connection to the IP Load Balancer
-func (p *NetscalerProvider) Connect() error {
+func (p *NetscalerProvider) Connect(ctx context.Context) error {
 	return nil
 }
 
+// Version returns the Citrix ADC software version
+func (p *NetscalerProvider) Version(ctx context.Context) (string, error) {
+	p.transport.SetContext(ctx)
+	v, err := p.client.FindResource(service.Nsversion.Type(), "")
+	if err != nil {
+		return "", fmt.Errorf("error getting Citrix ADC version: %v", err)
+	}
+	version, _ := v["version"].(string)
+	return version, nil
+}
+
 // Close closes the connection to the IP Load Balancer
-func (p *NetscalerProvider) Close() error {
+func (p *NetscalerProvider) Close(ctx context.Context) error {
+	p.transport.SetContext(ctx)
+	if !p.saveconfig {
+		return nil
+	}
 	return saveConfig(p, "close connection")
 }
 
@@ -121,8 +212,13 @@ func (p *NetscalerProvider) Close() error {
 // Monitor Management
 // ----------------------------------------
 
+// UntaggedMonitor marks the Citrix ADC monitors as untagged since the lbmonitor has no
+// comment to store the ownership tag
+func (p *NetscalerProvider) UntaggedMonitor() {}
+
 // GetMonitor gets a monitor in the IP Load Balancer
-func (p *NetscalerProvider) GetMonitor(monitor *lbv1.Monitor) (*lbv1.Monitor, error) {
+func (p *NetscalerProvider) GetMonitor(ctx context.Context, monitor *lbv1.Monitor) (*lbv1.Monitor, error) {
+	p.transport.SetContext(ctx)
 	m, _ := p.client.FindResource(service.Lbmonitor.Type(), monitor.Name)
 	// if err != nil {
 	// 	return nil, err
@@ -133,13 +229,50 @@ func (p *NetscalerProvider) GetMonitor(monitor *lbv1.Monitor) (*lbv1.Monitor, er
 	}
 
 	// Return monitor details in case it exists
-	path := strings.TrimLeft(m["httprequest"].(string), "GET ")
+	request, _ := m["httprequest"].(string)
+	if send, ok := m["send"].(string); ok && request == "" {
+		request = send
+	}
+	method, path, _ := strings.Cut(request, " ")
 
 	mon := &lbv1.Monitor{
 		Name:        m["monitorname"].(string),
-		MonitorType: m["type"].(string),
+		MonitorType: strings.ToLower(m["type"].(string)),
 		Path:        path,
 		Port:        int(m["destport"].(float64)),
+		Method:      method,
+		Interval:    nitroInt(m["interval"]),
+		Timeout:     nitroInt(m["resptimeout"]),
+	}
+	// The default response timeout is reported as unset so it matches the requested monitor
+	if monitor.Timeout == 0 && mon.Timeout == lbv1.DefaultCitrixADCMonitorTimeout {
+		mon.Timeout = 0
+	}
+	switch {
+	case strings.EqualFold(mon.MonitorType, monitorTypeHTTPECV):
+		mon.MonitorType = lbv1.MonitorTypeHTTP
+	case strings.EqualFold(mon.MonitorType, monitorTypeTCPECV):
+		mon.MonitorType = lbv1.MonitorTypeTCP
+		mon.Send, _ = m["send"].(string)
+	}
+	if mon.MonitorType == lbv1.MonitorTypeTCP {
+		mon.Path, mon.Method, mon.Host = monitor.Path, monitor.Method, monitor.Host
+	}
+	if recv, ok := m["recv"].(string); ok {
+		mon.Receive = recv
+	}
+	if headers, ok := m["customheaders"].(string); ok {
+		mon.Host = strings.TrimSuffix(strings.TrimPrefix(headers, "Host: "), "\r\n")
+	}
+	// Rise, fall and the response code use the Netscaler defaults if not set so they are only compared when requested
+	if monitor.Rise != 0 {
+		mon.Rise = nitroInt(m["successretries"])
+	}
+	if monitor.Fall != 0 {
+		mon.Fall = nitroInt(m["retries"])
+	}
+	if codes, ok := m["respcode"].([]interface{}); ok && len(codes) == 1 && monitor.ExpectedStatus != 0 {
+		mon.ExpectedStatus, _ = strconv.Atoi(fmt.Sprint(codes[0]))
 	}
 
 	if m["secure"] == yesValue {
@@ -151,25 +284,10 @@ func (p *NetscalerProvider) GetMonitor(monitor *lbv1.Monitor) (*lbv1.Monitor, er
 
 // CreateMonitor creates a monitor in the IP Load Balancer
 // if port argument is 0, no port override is configured
-func (p *NetscalerProvider) CreateMonitor(m *lbv1.Monitor) error {
-	lbMonitor := lb.Lbmonitor{
-		Monitorname: m.Name,
-		Type:        monitorTypeHTTP,
-		Interval:    5,
-		Downtime:    16,
-		Httprequest: "GET " + m.Path,
-	}
-
-	if m.Port != 0 {
-		lbMonitor.Destport = m.Port
-	}
-	// on Netscaler, HTTP and HTTPS are the same with different flags
-	if m.MonitorType == httpsProtocol {
-		lbMonitor.Secure = yesValue
-		lbMonitor.Sslprofile = "ns_default_ssl_profile_backend"
-	}
-
-	name, err := p.client.AddResource(service.Lbmonitor.Type(), m.Name, &lbMonitor)
+func (p *NetscalerProvider) CreateMonitor(ctx context.Context, m *lbv1.Monitor) error {
+	p.transport.SetContext(ctx)
+	lbMonitor := nitroMonitor(m)
+	name, err := p.client.AddResource(service.Lbmonitor.Type(), m.Name, lbMonitor)
 	if err != nil {
 		return fmt.Errorf("error creating Netscaler monitor %s: %v", name, err)
 	}
@@ -179,13 +297,51 @@ func (p *NetscalerProvider) CreateMonitor(m *lbv1.Monitor) error {
 
 // EditMonitor edits a monitor in the IP Load Balancer
 // if port argument is 0, no port override is configured
-func (p *NetscalerProvider) EditMonitor(m *lbv1.Monitor) error {
-	lbMonitor := lb.Lbmonitor{
-		Monitorname: m.Name,
-		Type:        monitorTypeHTTP,
-		Interval:    5,
-		Downtime:    16,
-		Httprequest: "GET " + m.Path,
+func (p *NetscalerProvider) EditMonitor(ctx context.Context, m *lbv1.Monitor) error {
+	p.transport.SetContext(ctx)
+	lbMonitor := nitroMonitor(m)
+	name, err := p.client.AddResource(service.Lbmonitor.Type(), m.Name, lbMonitor)
+	if err != nil {
+		return fmt.Errorf("error creating Netscaler monitor %s: %v", name, err)
+	}
+	return nil
+}
+
+// nitroMonitor builds the Netscaler monitor from the monitor spec.
+// A HTTP-ECV monitor is used when a receive string is expected in the response.
+// The timeout is the probe response timeout, which must be less than the interval.
+func nitroMonitor(monitor *lbv1.Monitor) *lb.Lbmonitor {
+	m := monitor.WithDefaults()
+	if monitor.Timeout == 0 {
+		m.Timeout = lbv1.DefaultCitrixADCMonitorTimeout
+	}
+	lbMonitor := &lb.Lbmonitor{
+		Monitorname:    m.Name,
+		Interval:       m.Interval,
+		Resptimeout:    m.Timeout,
+		Successretries: m.Rise,
+		Retries:        m.Fall,
+	}
+	switch {
+	case m.MonitorType == lbv1.MonitorTypeTCP && (m.Send != "" || m.Receive != ""):
+		lbMonitor.Type = monitorTypeTCPECV
+		lbMonitor.Send = m.Send
+		lbMonitor.Recv = m.Receive
+	case m.MonitorType == lbv1.MonitorTypeTCP:
+		lbMonitor.Type = monitorTypeTCP
+	case m.Receive != "":
+		lbMonitor.Type = monitorTypeHTTPECV
+		lbMonitor.Send = m.Method + " " + m.Path
+		lbMonitor.Recv = m.Receive
+	default:
+		lbMonitor.Type = monitorTypeHTTP
+		lbMonitor.Httprequest = m.Method + " " + m.Path
+		if m.ExpectedStatus != 0 {
+			lbMonitor.Respcode = []string{strconv.Itoa(m.ExpectedStatus)}
+		}
+	}
+	if m.Host != "" && m.MonitorType != lbv1.MonitorTypeTCP {
+		lbMonitor.Customheaders = "Host: " + m.Host + "\r\n"
 	}
 
 	if m.Port != 0 {
@@ -196,27 +352,29 @@ func (p *NetscalerProvider) EditMonitor(m *lbv1.Monitor) error {
 		lbMonitor.Secure = yesValue
 		lbMonitor.Sslprofile = "ns_default_ssl_profile_backend"
 	}
+	return lbMonitor
+}
 
-	name, err := p.client.AddResource(service.Lbmonitor.Type(), m.Name, &lbMonitor)
-	if err != nil {
-		return fmt.Errorf("error creating Netscaler monitor %s: %v", name, err)
+// nitroInt converts a numeric NITRO attribute to int
+func nitroInt(v interface{}) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
 	}
-	return nil
+	return 0
 }
 
 // DeleteMonitor deletes a monitor in the IP Load Balancer
-func (p *NetscalerProvider) DeleteMonitor(m *lbv1.Monitor) error {
+func (p *NetscalerProvider) DeleteMonitor(ctx context.Context, m *lbv1.Monitor) error {
+	p.transport.SetContext(ctx)
 	// err := p.client.DeleteResource(service.Lbmonitor.Type(), m.Name)
 
-	var t string
-	if m.MonitorType == httpsProtocol {
-		t = monitorTypeHTTP
-	} else {
-		t = m.MonitorType
-	}
 	var args = []string{
 		"monitorname:" + m.Name,
-		"type:" + t,
+		"type:" + strings.ToLower(nitroMonitor(m).Type),
 	}
 	err := p.client.DeleteResourceWithArgs(service.Lbmonitor.Type(), m.Name, args)
 
@@ -231,7 +389,8 @@ func (p *NetscalerProvider) DeleteMonitor(m *lbv1.Monitor) error {
 // ----------------------------------------
 
 // GetPool gets a server pool from the Load Balancer
-func (p *NetscalerProvider) GetPool(pool *lbv1.Pool) (*lbv1.Pool, error) {
+func (p *NetscalerProvider) GetPool(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
+	p.transport.SetContext(ctx)
 	m, _ := p.client.FindResource(service.Servicegroup.Type(), pool.Name)
 	// if err != nil {
 	// 	return nil, err
@@ -256,19 +415,25 @@ func (p *NetscalerProvider) GetPool(pool *lbv1.Pool) (*lbv1.Pool, error) {
 		monitor = poolMonitor["monitor_name"].(string)
 	}
 
+	// The comment has the ownership tag
+	owner, _ := m["comment"].(string)
+
 	retPool := &lbv1.Pool{
 		Name:    name,
 		Monitor: monitor,
+		Owner:   owner,
 	}
 
 	return retPool, nil
 }
 
 // CreatePool creates a server pool in the Load Balancer
-func (p *NetscalerProvider) CreatePool(pool *lbv1.Pool) error {
+func (p *NetscalerProvider) CreatePool(ctx context.Context, pool *lbv1.Pool) error {
+	p.transport.SetContext(ctx)
 	nsSvcGrp := &basic.Servicegroup{
 		Servicegroupname: pool.Name,
-		Servicetype:      serviceTypeTCP,
+		Servicetype:      serviceType(pool.Protocol),
+		Comment:          pool.Owner,
 	}
 	_, err := p.client.AddResource(service.Servicegroup.Type(), pool.Name, nsSvcGrp)
 	if err != nil {
@@ -288,10 +453,12 @@ func (p *NetscalerProvider) CreatePool(pool *lbv1.Pool) error {
 }
 
 // EditPool modifies a server pool in the Load Balancer
-func (p *NetscalerProvider) EditPool(pool *lbv1.Pool) error {
+func (p *NetscalerProvider) EditPool(ctx context.Context, pool *lbv1.Pool) error {
+	p.transport.SetContext(ctx)
 	nsSvcGrp := &basic.Servicegroup{
 		Servicegroupname: pool.Name,
-		Servicetype:      serviceTypeTCP,
+		Servicetype:      serviceType(pool.Protocol),
+		Comment:          pool.Owner,
 	}
 	_, err := p.client.AddResource(service.Servicegroup.Type(), pool.Name, nsSvcGrp)
 	if err != nil {
@@ -311,7 +478,8 @@ func (p *NetscalerProvider) EditPool(pool *lbv1.Pool) error {
 }
 
 // DeletePool removes a server pool in the Load Balancer
-func (p *NetscalerProvider) DeletePool(pool *lbv1.Pool) error {
+func (p *NetscalerProvider) DeletePool(ctx context.Context, pool *lbv1.Pool) error {
+	p.transport.SetContext(ctx)
 	err := p.client.DeleteResource(service.Servicegroup.Type(), pool.Name)
 	if err != nil {
 		return fmt.Errorf("error deleting pool %s: %v", pool.Name, err)
@@ -325,7 +493,8 @@ func (p *NetscalerProvider) DeletePool(pool *lbv1.Pool) error {
 // ----------------------------------------
 
 // GetPoolMembers gets the pool members and return them in Pool object
-func (p *NetscalerProvider) GetPoolMembers(pool *lbv1.Pool) (*lbv1.Pool, error) {
+func (p *NetscalerProvider) GetPoolMembers(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
+	p.transport.SetContext(ctx)
 	var members []lbv1.PoolMember
 	poolBinding, err := p.client.FindResource(service.Servicegroup_binding.Type(), pool.Name)
 	if err != nil {
@@ -339,7 +508,7 @@ func (p *NetscalerProvider) GetPoolMembers(pool *lbv1.Pool) (*lbv1.Pool, error)
 			mem := member.(map[string]interface{})
 			ip := mem["servername"].(string)
 			port := int(mem["port"].(float64))
-			name := ip + ":" + strconv.Itoa(port)
+			name := net.JoinHostPort(ip, strconv.Itoa(port))
 
 			node := &lbv1.Node{
 				Name: name,
@@ -358,7 +527,8 @@ func (p *NetscalerProvider) GetPoolMembers(pool *lbv1.Pool) (*lbv1.Pool, error)
 }
 
 // CreatePoolMember creates a member to be added to pool in the Load Balancer
-func (p *NetscalerProvider) CreatePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool) error {
+func (p *NetscalerProvider) CreatePoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
+	p.transport.SetContext(ctx)
 	p.log.Info("Creating Node", "node", m.Node.Name, "host", m.Node.Host)
 
 	nsServer := basic.Server{
@@ -387,12 +557,28 @@ func (p *NetscalerProvider) CreatePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool
 
 // EditPoolMember modifies a server pool member in the Load Balancer
 // status could be "enable" or "disable"
-func (p *NetscalerProvider) EditPoolMember(m *lbv1.PoolMember, pool *lbv1.Pool, status string) error {
+func (p *NetscalerProvider) EditPoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool, status string) error {
+	p.transport.SetContext(ctx)
+	p.log.Info("Editing Node", "node", m.Node.Name, "host", m.Node.Host, "status", status)
+	member := basic.Servicegroup{
+		Servicegroupname: pool.Name,
+		Servername:       m.Node.Host,
+		Port:             m.Port,
+	}
+	// Disabled members keep the existing connections until they are closed
+	if status == backend.MemberStatusDisable {
+		member.Graceful = "YES"
+	}
+	err := p.client.ActOnResource(service.Servicegroup.Type(), &member, status)
+	if err != nil {
+		return fmt.Errorf("error editing member %s in pool %s: %v", m.Node.Host, pool.Name, err)
+	}
 	return nil
 }
 
 // DeletePoolMember deletes a member in the Load Balancer
-func (p *NetscalerProvider) DeletePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool) error {
+func (p *NetscalerProvider) DeletePoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
+	p.transport.SetContext(ctx)
 	p.log.Info("Deleting Node", "node", m.Node.Name, "host", m.Node.Host)
 	svcName := m.Node.Host
 
@@ -427,7 +613,8 @@ func (p *NetscalerProvider) DeletePoolMember(m *lbv1.PoolMember, pool *lbv1.Pool
 // ----------------------------------------
 
 // GetVIP gets a VIP in the IP Load Balancer
-func (p *NetscalerProvider) GetVIP(v *lbv1.VIP) (*lbv1.VIP, error) {
+func (p *NetscalerProvider) GetVIP(ctx context.Context, v *lbv1.VIP) (*lbv1.VIP, error) {
+	p.transport.SetContext(ctx)
 	vs, _ := p.client.FindResource(service.Lbvserver.Type(), v.Name)
 	// if err != nil {
 	// 	return nil, err
@@ -439,11 +626,13 @@ func (p *NetscalerProvider) GetVIP(v *lbv1.VIP) (*lbv1.VIP, error) {
 
 	// fmt.Printf("VIP: %+v\n", vs)
 	// Return VIP details in case it exists
+	owner, _ := vs["comment"].(string)
 	vip := &lbv1.VIP{
-		Name: vs["name"].(string),
-		IP:   vs["ipv46"].(string),
-		Port: int(vs["port"].(float64)),
-		Pool: v.Pool,
+		Name:  vs["name"].(string),
+		IP:    vs["ipv46"].(string),
+		Port:  int(vs["port"].(float64)),
+		Pool:  v.Pool,
+		Owner: owner,
 	}
 
 	poolBinding, err := p.client.FindResource(service.Lbvserver_servicegroup_binding.Type(), v.Name)
@@ -457,13 +646,15 @@ func (p *NetscalerProvider) GetVIP(v *lbv1.VIP) (*lbv1.VIP, error) {
 }
 
 // CreateVIP creates a Virtual Server in the Load Balancer
-func (p *NetscalerProvider) CreateVIP(v *lbv1.VIP) error {
+func (p *NetscalerProvider) CreateVIP(ctx context.Context, v *lbv1.VIP) error {
+	p.transport.SetContext(ctx)
 	nsLB := lb.Lbvserver{
 		Name:        v.Name,
 		Ipv46:       v.IP,
 		Port:        v.Port,
-		Servicetype: serviceTypeTCP,
+		Servicetype: serviceType(v.Protocol),
 		Lbmethod:    p.lbmethod,
+		Comment:     v.Owner,
 	}
 	_, err := p.client.AddResource(service.Lbvserver.Type(), v.Name, &nsLB)
 
@@ -486,13 +677,15 @@ func (p *NetscalerProvider) CreateVIP(v *lbv1.VIP) error {
 }
 
 // EditVIP modifies a Virtual Server in the Load Balancer
-func (p *NetscalerProvider) EditVIP(v *lbv1.VIP) error {
+func (p *NetscalerProvider) EditVIP(ctx context.Context, v *lbv1.VIP) error {
+	p.transport.SetContext(ctx)
 	nsLB := lb.Lbvserver{
 		Name:        v.Name,
 		Ipv46:       v.IP,
 		Port:        v.Port,
-		Servicetype: serviceTypeTCP,
+		Servicetype: serviceType(v.Protocol),
 		Lbmethod:    p.lbmethod,
+		Comment:     v.Owner,
 	}
 	_, err := p.client.AddResource(service.Lbvserver.Type(), v.Name, &nsLB)
 
@@ -514,7 +707,8 @@ func (p *NetscalerProvider) EditVIP(v *lbv1.VIP) error {
 }
 
 // DeleteVIP removes a VIP
-func (p *NetscalerProvider) DeleteVIP(v *lbv1.VIP) error {
+func (p *NetscalerProvider) DeleteVIP(ctx context.Context, v *lbv1.VIP) error {
+	p.transport.SetContext(ctx)
 	err := p.client.DeleteResource(service.Lbvserver.Type(), v.Name)
 	if err != nil {
 		return fmt.Errorf("error deleting VIP %s: %v", v.Name, err)
@@ -529,3 +723,11 @@ func saveConfig(p *NetscalerProvider, msg string) error {
 	p.log.Info("Configuration saved")
 	return nil
 }
+
+// serviceType returns the NITRO service type for the pool or VIP protocol
+func serviceType(protocol string) string {
+	if protocol == lbv1.ProtocolUDP {
+		return serviceTypeUDP
+	}
+	return serviceTypeTCP
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/tidwall/gjson"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	lbv1 "github.com/carlosedp/lbconfig-operator/api/v1"
 	. "github.com/carlosedp/lbconfig-operator/internal/controller/backend/backend_controller"
@@ -92,7 +93,6 @@ var loadBalancer = &lbv1.ExternalLoadBalancer{
 			Host:          "",
 			Port:          0,
 			Creds:         credsSecret.Name,
-			Partition:     "Common",
 			ValidateCerts: false,
 			// Debug:         true,
 		},
@@ -146,6 +146,7 @@ type httpdataStruct struct {
 	method string
 	data   string
 	post   map[string][]string
+	header http.Header
 }
 
 var _ = Describe("When using a Netscaler backend", func() {
@@ -158,6 +159,7 @@ var _ = Describe("When using a Netscaler backend", func() {
 			// GinkgoWriter.Printf("Received a request for %s\n", r.URL.String())
 			httpdata.url = r.URL.String()
 			httpdata.method = r.Method
+			httpdata.header = r.Header.Clone()
 			body, _ := io.ReadAll(r.Body)
 			httpdata.data = string(body)
 			for k, v := range r.Form {
@@ -191,45 +193,91 @@ var _ = Describe("When using a Netscaler backend", func() {
 	It("Should connect to the backend", func() {
 		createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 		Expect(err).ToNot(HaveOccurred())
-		err = createdBackend.Provider.Connect()
+		err = createdBackend.Provider.Connect(ctx)
 		Expect(err).ToNot(HaveOccurred())
 	})
 
 	It("Should close connection", func() {
 		createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 		Expect(err).ToNot(HaveOccurred())
-		err = createdBackend.Provider.Connect()
+		err = createdBackend.Provider.Connect(ctx)
 		Expect(err).ToNot(HaveOccurred())
-		err = createdBackend.Provider.Close()
+		err = createdBackend.Provider.Close(ctx)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("Should not save the configuration when disabled in the citrixADC options", func() {
+		provider := loadBalancer.Spec.Provider.DeepCopy()
+		provider.CitrixADC = &lbv1.CitrixADCOptions{SaveConfig: ptr.To(false)}
+		createdBackend, err := CreateBackend(ctx, provider, "username", "password")
+		Expect(err).ToNot(HaveOccurred())
+		httpdata.url = ""
+		err = createdBackend.Provider.Close(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Consistently(func() string { return httpdata.url }, time.Second, interval).Should(BeEmpty())
+	})
+
+	It("Should request the backend version", func() {
+		createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
+		Expect(err).ToNot(HaveOccurred())
+		_, _ = createdBackend.Version(ctx)
+		Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/nsversion"))
+		Eventually(httpdata.method, timeout, interval).Should(Equal("GET"))
+	})
+
+	It("Should not send requests after the context is cancelled", func() {
+		createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
+		Expect(err).ToNot(HaveOccurred())
+		httpdata.url = ""
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = createdBackend.Version(cancelled)
+		Expect(err).To(HaveOccurred())
+		Expect(httpdata.url).To(BeEmpty())
+	})
+
+	It("Should send the requests of each provider through its own transport", func() {
+		createdBackend, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
+		Expect(err).ToNot(HaveOccurred())
+		other, err := CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
+		Expect(err).ToNot(HaveOccurred())
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = other.Version(cancelled)
+		Expect(err).To(HaveOccurred())
+
+		httpdata.url = ""
+		_, _ = createdBackend.Version(ctx)
+		Expect(httpdata.url).NotTo(BeEmpty())
+		Expect(httpdata.header.Get("X-Lbconfig-Client")).To(BeEmpty())
+	})
+
 	Context("when handling load balancer monitors", func() {
 		var createdBackend *BackendController
 		var err error
 		BeforeEach(func() {
 			createdBackend, err = CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 			Expect(err).ToNot(HaveOccurred())
-			err = createdBackend.Provider.Connect()
+			err = createdBackend.Provider.Connect(ctx)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("Should get a monitor", func() {
-			_, _ = createdBackend.Provider.GetMonitor(monitor)
+			_, _ = createdBackend.Provider.GetMonitor(ctx, monitor)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/lbmonitor/test-monitor"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("GET"))
 			// Expect(err).ToNot(HaveOccurred())
 		})
 
 		It("Should create a monitor", func() {
-			err = createdBackend.Provider.CreateMonitor(monitor)
+			err = createdBackend.Provider.CreateMonitor(ctx, monitor)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/lbmonitor?idempotent=yes"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("POST"))
 
 			// <map[string]interface {} | len:1>: {
 			// 	"lbmonitor": <map[string]interface {} | len:7>{
 			// 		"destport": <float64>80,
-			// 		"downtime": <float64>16,
+			// 		"resptimeout": <float64>2,
 			// 		"httprequest": <string>"GET /health",
 			// 		"interval": <float64>5,
 			// 		"monitorname": <string>"test-monitor",
@@ -241,24 +289,48 @@ var _ = Describe("When using a Netscaler backend", func() {
 			Eventually(func() string { return gjson.Get(httpdata.data, "lbmonitor.httprequest").String() }, timeout, interval).Should(Equal("GET /health"))
 			Eventually(func() string { return gjson.Get(httpdata.data, "lbmonitor.monitorname").String() }, timeout, interval).Should(Equal("test-monitor"))
 			Eventually(func() string { return gjson.Get(httpdata.data, "lbmonitor.type").String() }, timeout, interval).Should(Equal("HTTP"))
+			// The timeout is the response timeout, defaulting to the Citrix ADC one
+			Eventually(func() string { return gjson.Get(httpdata.data, "lbmonitor.resptimeout").String() }, timeout, interval).Should(Equal("2"))
+			Expect(gjson.Get(httpdata.data, "lbmonitor.downtime").Exists()).To(BeFalse())
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should set the monitor timeout as the response timeout", func() {
+			timedMonitor := *monitor
+			timedMonitor.Interval = 10
+			timedMonitor.Timeout = 4
+			err = createdBackend.Provider.EditMonitor(ctx, &timedMonitor)
+			Eventually(func() string { return gjson.Get(httpdata.data, "lbmonitor.resptimeout").String() }, timeout, interval).Should(Equal("4"))
+			Eventually(func() string { return gjson.Get(httpdata.data, "lbmonitor.interval").String() }, timeout, interval).Should(Equal("10"))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should create a tcp monitor with send and receive strings", func() {
+			tcpMonitor := &lbv1.Monitor{Name: "test-tcp-monitor", MonitorType: "tcp", Port: 1883, Send: "PING", Receive: "PONG"}
+			err = createdBackend.Provider.CreateMonitor(ctx, tcpMonitor)
+			Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/lbmonitor?idempotent=yes"))
+			Eventually(func() string { return gjson.Get(httpdata.data, "lbmonitor.type").String() }, timeout, interval).Should(Equal("TCP-ECV"))
+			Eventually(func() string { return gjson.Get(httpdata.data, "lbmonitor.send").String() }, timeout, interval).Should(Equal("PING"))
+			Eventually(func() string { return gjson.Get(httpdata.data, "lbmonitor.recv").String() }, timeout, interval).Should(Equal("PONG"))
+			Expect(gjson.Get(httpdata.data, "lbmonitor.httprequest").Exists()).To(BeFalse())
 			Expect(err).ToNot(HaveOccurred())
 		})
 
 		It("Should delete the monitor", func() {
-			err = createdBackend.Provider.DeleteMonitor(monitor)
+			err = createdBackend.Provider.DeleteMonitor(ctx, monitor)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/lbmonitor/test-monitor?args=monitorname:test-monitor,type:http"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("DELETE"))
 			Expect(err).ToNot(HaveOccurred())
 		})
 
 		It("Should edit the monitor", func() {
-			err = createdBackend.Provider.EditMonitor(monitor)
+			err = createdBackend.Provider.EditMonitor(ctx, monitor)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/lbmonitor?idempotent=yes"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("POST"))
 			// <map[string]interface {} | len:1>: {
 			// 	"lbmonitor": <map[string]interface {} | len:7>{
 			// 		"destport": <float64>80,
-			// 		"downtime": <float64>16,
+			// 		"resptimeout": <float64>2,
 			// 		"httprequest": <string>"GET /health",
 			// 		"interval": <float64>5,
 			// 		"monitorname": <string>"test-monitor",
@@ -280,18 +352,18 @@ var _ = Describe("When using a Netscaler backend", func() {
 		BeforeEach(func() {
 			createdBackend, err = CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 			Expect(err).ToNot(HaveOccurred())
-			err = createdBackend.Provider.Connect()
+			err = createdBackend.Provider.Connect(ctx)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("Should get a pool", func() {
-			_, _ = createdBackend.Provider.GetPool(pool)
+			_, _ = createdBackend.Provider.GetPool(ctx, pool)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/servicegroup/test-pool"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("GET"))
 		})
 
 		It("Should create a pool", func() {
-			err = createdBackend.Provider.CreatePool(pool)
+			err = createdBackend.Provider.CreatePool(ctx, pool)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/servicegroup_lbmonitor_binding"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("POST"))
 			// <map[string]interface {} | len:1>: {
@@ -305,14 +377,14 @@ var _ = Describe("When using a Netscaler backend", func() {
 		})
 
 		It("Should delete the pool", func() {
-			err = createdBackend.Provider.DeletePool(pool)
+			err = createdBackend.Provider.DeletePool(ctx, pool)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/servicegroup/test-pool"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("DELETE"))
 			Expect(err).ToNot(HaveOccurred())
 		})
 
 		It("Should edit the pool", func() {
-			err = createdBackend.Provider.EditPool(pool)
+			err = createdBackend.Provider.EditPool(ctx, pool)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/servicegroup_lbmonitor_binding"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("POST"))
 			// <map[string]interface {} | len:1>: {
@@ -326,38 +398,38 @@ var _ = Describe("When using a Netscaler backend", func() {
 		})
 
 		It("Should get pool members", func() {
-			_, _ = createdBackend.Provider.GetPoolMembers(pool)
+			_, _ = createdBackend.Provider.GetPoolMembers(ctx, pool)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/servicegroup_binding/test-pool"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("GET"))
 			Expect(err).NotTo(HaveOccurred())
 		})
 		It("Should create pool members", func() {
-			_ = createdBackend.Provider.CreatePoolMember(poolmember, pool)
+			_ = createdBackend.Provider.CreatePoolMember(ctx, poolmember, pool)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/servicegroup_servicegroupmember_binding"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("POST"))
 			// Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("Should delete pool members", func() {
-			err = createdBackend.Provider.DeletePoolMember(poolmember, pool)
+			err = createdBackend.Provider.DeletePoolMember(ctx, poolmember, pool)
 			Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/servicegroup_servicegroupmember_binding/test-pool?args=servername:1.1.1.5,servicegroupname:test-pool,port:80"))
 			Eventually(httpdata.method, timeout, interval).Should(Equal("DELETE"))
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("Should edit pool members", func() {
-			// // Enable
-			err = createdBackend.Provider.EditPoolMember(poolmember, pool, "enable")
-			// Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/pool/test-pool/members/1.1.1.5:80"))
-			// Eventually(httpdata.method, timeout, interval).Should(Equal("PUT"))
-			// Eventually(gjson.Get(httpdata.data, "session").String(), timeout, interval).Should(Equal("user-enabled"))
-			// Expect(err).NotTo(HaveOccurred())
-			// // Disable
-			// err = createdBackend.Provider.EditPoolMember(poolmember, pool, "disable")
-			// Eventually(httpdata.url, timeout, interval).Should(Equal("/mgmt/tm/ltm/pool/test-pool/members/1.1.1.5:80"))
-			// Eventually(httpdata.method, timeout, interval).Should(Equal("PUT"))
-			// Expect(httpdata.data).Should(Equal(""))
-			// Eventually(gjson.Get(httpdata.data, "session").String(), timeout, interval).Should(Equal("user-disabled"))
+			// Enable
+			err = createdBackend.Provider.EditPoolMember(ctx, poolmember, pool, "enable")
+			Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/servicegroup?action=enable"))
+			Eventually(httpdata.method, timeout, interval).Should(Equal("POST"))
+			Eventually(func() string { return gjson.Get(httpdata.data, "servicegroup.servername").String() }, timeout, interval).Should(Equal("1.1.1.5"))
+			Eventually(func() int64 { return gjson.Get(httpdata.data, "servicegroup.port").Int() }, timeout, interval).Should(Equal(int64(80)))
+			Expect(err).NotTo(HaveOccurred())
+			// Disable
+			err = createdBackend.Provider.EditPoolMember(ctx, poolmember, pool, "disable")
+			Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/servicegroup?action=disable"))
+			Eventually(httpdata.method, timeout, interval).Should(Equal("POST"))
+			Eventually(func() string { return gjson.Get(httpdata.data, "servicegroup.graceful").String() }, timeout, interval).Should(Equal("YES"))
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -367,25 +439,25 @@ var _ = Describe("When using a Netscaler backend", func() {
 			BeforeEach(func() {
 				createdBackend, err = CreateBackend(ctx, &loadBalancer.Spec.Provider, "username", "password")
 				Expect(err).ToNot(HaveOccurred())
-				err = createdBackend.Provider.Connect()
+				err = createdBackend.Provider.Connect(ctx)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
 			It("Should get a VIP", func() {
-				_, _ = createdBackend.Provider.GetVIP(VIP)
+				_, _ = createdBackend.Provider.GetVIP(ctx, VIP)
 				Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/lbvserver/test-vip"))
 				Eventually(httpdata.method, timeout, interval).Should(Equal("GET"))
 				Expect(err).NotTo(HaveOccurred())
 			})
 
 			It("Should create a VIP", func() {
-				err = createdBackend.Provider.CreateVIP(VIP)
+				err = createdBackend.Provider.CreateVIP(ctx, VIP)
 				Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/lbvserver_servicegroup_binding"))
 				Eventually(httpdata.method, timeout, interval).Should(Equal("POST"))
 				// <map[string]interface {} | len:5>: {
 				// 	"lbmonitor": <map[string]interface {} | len:6>{
 				// 		"interval": <float64>5,
-				// 		"downtime": <float64>16,
+				// 		"resptimeout": <float64>2,
 				// 		"destport": <float64>80,
 				// 		"monitorname": <string>"test-monitor",
 				// 		"type": <string>"HTTP",
@@ -418,14 +490,14 @@ var _ = Describe("When using a Netscaler backend", func() {
 			})
 
 			It("Should delete the VIP", func() {
-				err = createdBackend.Provider.DeleteVIP(VIP)
+				err = createdBackend.Provider.DeleteVIP(ctx, VIP)
 				Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/lbvserver/test-vip"))
 				Eventually(httpdata.method, timeout, interval).Should(Equal("DELETE"))
 				Expect(err).NotTo(HaveOccurred())
 			})
 
 			It("Should edit the VIP", func() {
-				err = createdBackend.Provider.EditVIP(VIP)
+				err = createdBackend.Provider.EditVIP(ctx, VIP)
 				Eventually(httpdata.url, timeout, interval).Should(Equal("/nitro/v1/config/lbvserver_servicegroup_binding"))
 				Eventually(httpdata.method, timeout, interval).Should(Equal("POST"))
 				// Expect(httpdata.data).To(Equal(""))
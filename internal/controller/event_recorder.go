@@ -0,0 +1,114 @@
+/*
+MIT License
+
+Copyright (c) 2022 Carlos Eduardo de Paula
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/lru"
+)
+
+const (
+	// eventCacheSize is the number of objects and events tracked by the rate limited recorder
+	eventCacheSize = 4096
+	// eventQPS and eventBurst limit the events emitted for each object
+	eventQPS   = 1.0 / 10
+	eventBurst = 25
+)
+
+// RateLimitedRecorder is an EventRecorder that drops repeated events so node flaps don't flood the API.
+// An event with the same object, type, reason and message is emitted at most once per interval and the
+// events for each object are limited by a token bucket.
+type RateLimitedRecorder struct {
+	recorder events.EventRecorder
+	interval time.Duration
+	clock    clock.PassiveClock
+
+	mu       sync.Mutex
+	seen     *lru.Cache
+	limiters *lru.Cache
+}
+
+var _ events.EventRecorder = &RateLimitedRecorder{}
+
+// NewRateLimitedRecorder wraps the recorder dropping identical events emitted within the interval
+func NewRateLimitedRecorder(recorder events.EventRecorder, interval time.Duration) *RateLimitedRecorder {
+	return newRateLimitedRecorderWithClock(recorder, interval, clock.RealClock{})
+}
+
+func newRateLimitedRecorderWithClock(recorder events.EventRecorder, interval time.Duration, c clock.PassiveClock) *RateLimitedRecorder {
+	return &RateLimitedRecorder{
+		recorder: recorder,
+		interval: interval,
+		clock:    c,
+		seen:     lru.New(eventCacheSize),
+		limiters: lru.New(eventCacheSize),
+	}
+}
+
+// Eventf emits the event if it's not rate limited
+func (r *RateLimitedRecorder) Eventf(regarding runtime.Object, related runtime.Object, eventtype, reason, action, note string, args ...interface{}) {
+	object := "unknown"
+	if accessor, err := meta.Accessor(regarding); err == nil {
+		object = string(accessor.GetUID()) + "/" + accessor.GetNamespace() + "/" + accessor.GetName()
+	}
+	message := fmt.Sprintf(note, args...)
+	if !r.allow(object, eventtype+"/"+reason+"/"+action+"/"+message) {
+		return
+	}
+	r.recorder.Eventf(regarding, related, eventtype, reason, action, "%s", message)
+}
+
+// allow checks if the event for the object was not emitted in the interval and the object has tokens left
+func (r *RateLimitedRecorder) allow(object string, event string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	key := object + "/" + event
+	if last, ok := r.seen.Get(key); ok && now.Sub(last.(time.Time)) < r.interval {
+		return false
+	}
+
+	var limiter flowcontrol.PassiveRateLimiter
+	if l, ok := r.limiters.Get(object); ok {
+		limiter = l.(flowcontrol.PassiveRateLimiter)
+	} else {
+		limiter = flowcontrol.NewTokenBucketPassiveRateLimiterWithClock(eventQPS, eventBurst, r.clock)
+		r.limiters.Add(object, limiter)
+	}
+	if !limiter.TryAccept() {
+		return false
+	}
+	r.seen.Add(key, now)
+	return true
+}
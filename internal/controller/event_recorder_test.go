@@ -0,0 +1,74 @@
+/*
+MIT License
+
+Copyright (c) 2022 Carlos Eduardo de Paula
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	lbv1 "github.com/carlosedp/lbconfig-operator/api/v1"
+)
+
+var _ = Describe("ExternalLoadBalancer event recorder", func() {
+	Context("When emitting events", func() {
+		It("Should drop repeated events within the interval", func() {
+			fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+			fake := events.NewFakeRecorder(eventBurst + 10)
+			recorder := newRateLimitedRecorderWithClock(fake, time.Minute, fakeClock)
+			lb := &lbv1.ExternalLoadBalancer{ObjectMeta: metav1.ObjectMeta{Name: "test-lb", Namespace: "default", UID: "uid-1"}}
+			other := &lbv1.ExternalLoadBalancer{ObjectMeta: metav1.ObjectMeta{Name: "other-lb", Namespace: "default", UID: "uid-2"}}
+
+			By("Emitting an event only once in the interval")
+			recorder.Eventf(lb, nil, corev1.EventTypeNormal, "MemberAdded", "CreatePoolMember", "Added member %s", "node-1")
+			recorder.Eventf(lb, nil, corev1.EventTypeNormal, "MemberAdded", "CreatePoolMember", "Added member %s", "node-1")
+			Expect(fake.Events).To(Receive(Equal("Normal MemberAdded Added member node-1")))
+			Expect(fake.Events).NotTo(Receive())
+
+			By("Emitting different events and events for other objects")
+			recorder.Eventf(lb, nil, corev1.EventTypeNormal, "MemberAdded", "CreatePoolMember", "Added member %s", "node-2")
+			recorder.Eventf(other, nil, corev1.EventTypeNormal, "MemberAdded", "CreatePoolMember", "Added member %s", "node-1")
+			Expect(fake.Events).To(HaveLen(2))
+			Eventually(fake.Events).Should(Receive())
+			Eventually(fake.Events).Should(Receive())
+
+			By("Emitting the event again after the interval")
+			fakeClock.SetTime(fakeClock.Now().Add(2 * time.Minute))
+			recorder.Eventf(lb, nil, corev1.EventTypeNormal, "MemberAdded", "CreatePoolMember", "Added member %s", "node-1")
+			Expect(fake.Events).To(Receive(Equal("Normal MemberAdded Added member node-1")))
+
+			By("Limiting the number of events for an object")
+			for i := range eventBurst * 2 {
+				recorder.Eventf(lb, nil, corev1.EventTypeNormal, "MemberAdded", "CreatePoolMember", "Added member node-%d", i+10)
+			}
+			Expect(len(fake.Events)).To(BeNumerically("<", eventBurst))
+		})
+	})
+})
@@ -26,21 +26,27 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"strconv"
 	"strings"
+	"time"
 
 	plog "log"
 
 	"github.com/go-logr/logr"
 	"github.com/prometheus/client_golang/prometheus"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -65,11 +71,52 @@ const (
 	trueStatus     = "True"
 )
 
+// Reasons used in the ExternalLoadBalancer status conditions
+const (
+	reasonReconciled           = "Reconciled"
+	reasonNoReadyNodes         = "NoReadyNodes"
+	reasonSecretNotFound       = "SecretNotFound"
+	reasonInvalidSpec          = "InvalidSpec"
+	reasonNodeListFailed       = "NodeListFailed"
+	reasonProviderCreateFailed = "ProviderCreateFailed"
+	reasonConnectionFailed     = "ConnectionFailed"
+	reasonMonitorSyncFailed    = "MonitorSyncFailed"
+	reasonPoolSyncFailed       = "PoolSyncFailed"
+	reasonVIPSyncFailed        = "VIPSyncFailed"
+	reasonStaleCleanupFailed   = "StaleCleanupFailed"
+	reasonCommitFailed         = "CommitFailed"
+	reasonRemovalRefused       = "MemberRemovalRefused"
+	reasonCleanupCompleted     = "CleanupCompleted"
+	reasonDriftDetected        = "DriftDetected"
+	reasonNoDrift              = "NoDrift"
+	reasonChangesPlanned       = "ChangesPlanned"
+	reasonNoChangesPlanned     = "NoChangesPlanned"
+	reasonPaused               = "Paused"
+	reasonResumed              = "Resumed"
+	reasonCleanupRetained      = "CleanupRetained"
+	reasonCleanupSkipped       = "CleanupSkipped"
+	reasonNotOwned             = "NotOwned"
+	reasonProviderMigrated     = "ProviderMigrated"
+	reasonMigrationFailed      = "ProviderMigrationFailed"
+	reasonMigrationRetained    = "ProviderMigrationRetained"
+	reasonProviderNotFound     = "ProviderNotFound"
+	reasonProviderNotAllowed   = "ProviderNotAllowed"
+)
+
 // ExternalLoadBalancerReconciler reconciles a ExternalLoadBalancer object
 type ExternalLoadBalancerReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
+	// SyncPeriod is the default interval the backend configuration is resynced to detect
+	// drift. Overridden by the ExternalLoadBalancer resyncperiod, zero disables the resync.
+	SyncPeriod time.Duration
+	// ClusterID identifies the cluster in the ownership tag set in the backend pools and VIPs
+	ClusterID string
+	// NamingTemplate is the default template of the backend object names. Overridden by the
+	// ExternalLoadBalancer namingtemplate, empty uses the controller.DefaultNamingTemplate.
+	NamingTemplate string
 }
 
 // Tracer name
@@ -78,6 +125,18 @@ const name = "github.com/carlosedp/lbconfig-operator"
 // ExternalLoadBalancerFinalizer is the finalizer object
 const ExternalLoadBalancerFinalizer = "lb.lbconfig.carlosedp.com/finalizer"
 
+// nodeAddressAnnotation is the node annotation with the comma separated addresses used
+// for the node in the pools, overriding the ExternalLoadBalancer nodeaddress policy
+const nodeAddressAnnotation = "lbconfig.carlosedp.com/node-address"
+
+// pausedAnnotation is the ExternalLoadBalancer annotation that pauses the reconciliation
+// when set to "true". The backend is not changed until the annotation is removed.
+const pausedAnnotation = "lbconfig.carlosedp.com/paused"
+
+// forceDeleteAnnotation is the ExternalLoadBalancer annotation that deletes the instance without
+// removing its configuration from the backend when set to "true", for an unreachable backend
+const forceDeleteAnnotation = "lbconfig.carlosedp.com/force-delete"
+
 // Definition of Prometheus metrics
 var (
 	metric_externallb = prometheus.NewGauge(
@@ -93,6 +152,20 @@ var (
 		},
 		[]string{"name", "namespace", "type", "vip", "port", "backend_vendor"},
 	)
+	metric_externallb_drift = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "externallb_drift",
+			Help: "Number of differences found between the backend and the applied configuration in the last sync",
+		},
+		[]string{"name", "namespace"},
+	)
+	metric_externallb_drift_detected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "externallb_drift_detected_total",
+			Help: "Total number of differences found between the backend and the applied configuration",
+		},
+		[]string{"name", "namespace"},
+	)
 )
 
 func init() {
@@ -102,7 +175,7 @@ func init() {
 		plog.SetFlags(0)
 	}
 	// Register custom metrics with the global prometheus registry
-	metrics.Registry.MustRegister(metric_externallb, metric_externallb_nodes)
+	metrics.Registry.MustRegister(metric_externallb, metric_externallb_nodes, metric_externallb_drift, metric_externallb_drift_detected)
 }
 
 // +kubebuilder:rbac:groups=lb.lbconfig.carlosedp.com,resources=externalloadbalancers,verbs=get;list;watch;create;update;patch;delete
@@ -110,6 +183,9 @@ func init() {
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=lb.lbconfig.carlosedp.com,resources=loadbalancerproviders,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 
 // Reconcile our ExternalLoadBalancer object
 func (r *ExternalLoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -155,7 +231,7 @@ func (r *ExternalLoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl
 		return r.Get(ctx, req.NamespacedName, lb)
 	}(ctx)
 	if err != nil {
-		if errors.IsNotFound(err) {
+		if apierrors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
@@ -172,36 +248,76 @@ func (r *ExternalLoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl
 	span.SetAttributes(attribute.String("lb.name", lb.Name), attribute.String("lb.provider", lb.Spec.Provider.Vendor))
 
 	// ----------------------------------------
-	// Set the Load Balancer backend
+	// Check if the ExternalLoadBalancer instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set. This is handled before any
+	// reconciliation so the configuration is not pushed again to the backend, and
+	// before the pause so the instance deletion doesn't wait for the annotation removal.
 	// ----------------------------------------
-	lbBackend := lb.Spec.Provider
-
-	// Get backend secret
-	credsSecret := &corev1.Secret{}
-	err = func(ctx context.Context) error {
-		_, span := otel.Tracer(name).Start(ctx, "Get Backend Secret")
-		span.SetAttributes(attribute.String("lb.name", lb.Name), attribute.String("lb.provider", lb.Spec.Provider.Vendor), attribute.String("lb.provider.secret", lbBackend.Creds))
+	isLoadBalancerMarkedToBeDeleted := func(ctx context.Context) bool {
+		_, span := otel.Tracer(name).Start(ctx, "GetDeletionTimestamp")
 		defer span.End()
-		return r.Get(ctx, types.NamespacedName{Name: lbBackend.Creds, Namespace: lb.Namespace}, credsSecret)
+		return lb.GetDeletionTimestamp() != nil
 	}(ctx)
 
+	if isLoadBalancerMarkedToBeDeleted {
+		err = r.reconcileDelete(ctx, lb)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			span.End()
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// ----------------------------------------
+	// Check if the ExternalLoadBalancer reconciliation is paused. This is checked after
+	// the deletion so a paused instance can still be deleted.
+	// ----------------------------------------
+	if isPaused(lb) {
+		logger.Info("ExternalLoadBalancer reconciliation is paused", "annotation", pausedAnnotation)
+		span.SetAttributes(attribute.Bool("lb.paused", true))
+		r.setPausedStatus(ctx, lb, true)
+		return ctrl.Result{}, nil
+	}
+	if meta.IsStatusConditionTrue(lb.Status.Conditions, lbv1.ConditionPaused) {
+		logger.Info("ExternalLoadBalancer reconciliation resumed, resyncing the backend configuration")
+		r.setPausedStatus(ctx, lb, false)
+	}
+
+	// ----------------------------------------
+	// Set the Load Balancer backend
+	// ----------------------------------------
+	lbBackend, reason, err := r.resolveProvider(ctx, lb)
+	if err != nil {
+		logger.Error(err, "unable to resolve the provider", "providerref", lb.Spec.ProviderRef)
+		r.setFailedStatus(ctx, lb, lbv1.ConditionSynced, reason, err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+		return ctrl.Result{}, err
+	}
+	span.SetAttributes(attribute.String("lb.providerref", lb.Spec.ProviderRef))
+
+	// Get backend secret
+	username, password, err := r.getProviderCredentials(ctx, lb, &lbBackend)
 	if err != nil {
 		logger.Error(err, "provider credentials secret not found")
+		r.setFailedStatus(ctx, lb, lbv1.ConditionCredentialsValid, reasonSecretNotFound, err)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		span.End()
 		return ctrl.Result{}, fmt.Errorf("provider credentials secret not found %v", err)
 		// return ctrl.Result{RequeueAfter: time.Second * 30}, nil
 	}
-	span.SetAttributes(attribute.String("lb.provider.secret", credsSecret.Name))
-	username := string(credsSecret.Data["username"])
-	password := string(credsSecret.Data["password"])
+	span.SetAttributes(attribute.String("lb.provider.secret", lbBackend.Creds))
 
 	// ----------------------------------------
 	// Get Nodes by role and label for infra router sharding or service exposure
 	// ----------------------------------------
-	if lb.Spec.Type == "" && lb.Spec.NodeLabels == nil {
-		err = fmt.Errorf("undefined loadbalancer type or no nodelabels defined")
+	if lb.Spec.Type == "" && lb.Spec.NodeLabels == nil && lb.Spec.NodeSelector == nil {
+		err = fmt.Errorf("undefined loadbalancer type or no nodelabels or nodeselector defined")
+		r.setFailedStatus(ctx, lb, lbv1.ConditionSynced, reasonInvalidSpec, err)
 		return ctrl.Result{Requeue: false}, err
 	}
 
@@ -211,17 +327,33 @@ func (r *ExternalLoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl
 		return computeLabels(*lb)
 	}(ctx)
 
+	selector, err := nodeSelector(*lb)
+	if err != nil {
+		err = fmt.Errorf("invalid nodeselector: %v", err)
+		r.setFailedStatus(ctx, lb, lbv1.ConditionSynced, reasonInvalidSpec, err)
+		return ctrl.Result{Requeue: false}, err
+	}
+	span.SetAttributes(attribute.String("lb.nodeselector", selector.String()))
+
+	addressPolicy, err := newNodeAddressPolicy(lb.Spec.NodeAddress)
+	if err != nil {
+		err = fmt.Errorf("invalid nodeaddress: %v", err)
+		r.setFailedStatus(ctx, lb, lbv1.ConditionSynced, reasonInvalidSpec, err)
+		return ctrl.Result{Requeue: false}, err
+	}
+
 	var nodeList corev1.NodeList
 	err = func(ctx context.Context) error {
 		_, span := otel.Tracer(name).Start(ctx, "Get NodeList")
 		defer span.End()
-		return r.List(ctx, &nodeList, client.MatchingLabels(labels))
+		return r.List(ctx, &nodeList, client.MatchingLabelsSelector{Selector: selector})
 	}(ctx)
 
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		logger.Error(err, "unable to list Nodes")
+		r.setFailedStatus(ctx, lb, lbv1.ConditionSynced, reasonNodeListFailed, err)
 		span.End()
 		return ctrl.Result{}, err
 	}
@@ -229,22 +361,37 @@ func (r *ExternalLoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl
 	// ----------------------------------------
 	// Check if node is eligible by label and status
 	// ----------------------------------------
-	var nodes []lbv1.Node
+	// Nodes are grouped by the address family of each VIP since pool members
+	// must use node addresses from the same family as the VIP.
+	vipIPs := vipAddresses(lb)
+	nodesByVIP := make(map[string][]lbv1.Node, len(vipIPs))
 	for _, n := range nodeList.Items {
 		logger.Info("Processing node", "node", n.Name, "labels", n.Labels)
+		if reason := nodeExclusionReason(&n, lb.Spec.ExcludeTaints); reason != "" {
+			logger.Info("Node excluded from the load balancer", "node", n.Name, "reason", reason)
+			continue
+		}
 		for _, cond := range n.Status.Conditions {
 			if cond.Type == readyCondition && cond.Status == trueStatus {
-				node := &lbv1.Node{
-					Name:   n.Name,
-					Host:   getNodeIP(&n),
-					Labels: labels,
+				for _, vipIP := range vipIPs {
+					ip := getNodeIP(&n, controller.IsIPv6(vipIP), addressPolicy)
+					if ip == "" {
+						logger.Info("Node has no address in the VIP address family", "node", n.Name, "vip", vipIP)
+						continue
+					}
+					node := &lbv1.Node{
+						Name:   n.Name,
+						Host:   ip,
+						Labels: labels,
+					}
+					logger.Info("Node matches", "node", node.Name, "labels", node.Labels, "ip", node.Host)
+					nodesByVIP[vipIP] = append(nodesByVIP[vipIP], *node)
 				}
-				logger.Info("Node matches", "node", node.Name, "labels", node.Labels, "ip", node.Host)
-				nodes = append(nodes, *node)
 			}
 		}
 
 	}
+	nodes := nodesByVIP[lb.Spec.Vip]
 	// Set metric to the number of nodes found
 	func(ctx context.Context) {
 		_, span := otel.Tracer(name).Start(ctx, "Metrics - Update metric_externallb_nodes")
@@ -252,8 +399,12 @@ func (r *ExternalLoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl
 		span.SetAttributes(attribute.String("metric.metric_externallb_nodes.lbname", lb.Name))
 		span.SetAttributes(attribute.Float64("metric.metric_externallb_nodes.nodes", float64(len(nodes))))
 
-		ports := strings.Trim(strings.Join(strings.Fields(fmt.Sprint(lb.Spec.Ports)), ","), "[]")
-		metric_externallb_nodes.WithLabelValues(lb.Name, lb.Namespace, lb.Spec.Type, lb.Spec.Vip, ports, lb.Spec.Provider.Vendor).Set(float64(len(nodes)))
+		ports := strings.Trim(strings.Join(strings.Fields(fmt.Sprint(servicePortNumbers(lb))), ","), "[]")
+		// Remove the metric for the previous ports if they changed
+		if prevPorts := strings.Trim(strings.Join(strings.Fields(fmt.Sprint(lb.Status.Ports)), ","), "[]"); prevPorts != "" && prevPorts != ports {
+			metric_externallb_nodes.DeleteLabelValues(lb.Name, lb.Namespace, lb.Spec.Type, lb.Spec.Vip, prevPorts, lbBackend.Vendor)
+		}
+		metric_externallb_nodes.WithLabelValues(lb.Name, lb.Namespace, lb.Spec.Type, lb.Spec.Vip, ports, lbBackend.Vendor).Set(float64(len(nodes)))
 	}(ctx)
 
 	// ----------------------------------------
@@ -261,6 +412,36 @@ func (r *ExternalLoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl
 	// ----------------------------------------
 	backend, err := controller.CreateBackend(ctx, &lbBackend, username, password)
 	if err != nil {
+		r.setFailedStatus(ctx, lb, lbv1.ConditionBackendReachable, reasonProviderCreateFailed, err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+		return ctrl.Result{}, err
+	}
+
+	backend.Recorder = r.Recorder
+	backend.Regarding = lb
+	// Drift is only checked against the configuration applied to the same backend
+	if lb.Status.Provider.Vendor == lbBackend.Vendor && lb.Status.Provider.Host == lbBackend.Host {
+		backend.Applied = lb.Status.DeepCopy()
+	}
+	backend.ObserveOnly = lb.Spec.Mode == lbv1.ModeObserveOnly
+	backend.Owner = controller.OwnerTag(r.ClusterID, lb.Namespace, lb.Name)
+	backend.Adopt = lb.Spec.Adopt
+	if lb.Spec.Mode == lbv1.ModePlan {
+		backend.Plan()
+	}
+	namingTemplate := lb.Spec.NamingTemplate
+	if namingTemplate == "" {
+		namingTemplate = r.NamingTemplate
+	}
+	// The instances applied with the previous default template keep their names
+	if (namingTemplate == "" || namingTemplate == controller.DefaultNamingTemplate) && usesLegacyNames(lb, backend, r.ClusterID) {
+		namingTemplate = controller.LegacyNamingTemplate
+	}
+	names, err := objectNames(lb, backend, namingTemplate, r.ClusterID)
+	if err != nil {
+		r.setFailedStatus(ctx, lb, lbv1.ConditionSynced, reasonInvalidSpec, err)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		span.End()
@@ -271,11 +452,12 @@ func (r *ExternalLoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl
 	// Connect to Backend Provider
 	// ----------------------------------------
 	err = func(ctx context.Context) error {
-		_, span := otel.Tracer(name).Start(ctx, "Provider - Connect")
+		ctx, span := otel.Tracer(name).Start(ctx, "Provider - Connect")
 		defer span.End()
-		return backend.Provider.Connect()
+		return backend.Provider.Connect(ctx)
 	}(ctx)
 	if err != nil {
+		r.setFailedStatus(ctx, lb, lbv1.ConditionBackendReachable, reasonConnectionFailed, err)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		span.End()
@@ -285,11 +467,12 @@ func (r *ExternalLoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl
 	// ----------------------------------------
 	// Handle Monitor
 	// ----------------------------------------
-	monitorName := "Monitor-" + lb.Name
-	lb.Spec.Monitor.Name = monitorName
+	lb.Spec.Monitor.Name = names[controller.KindMonitor+"/"]
 	monitor := lb.Spec.Monitor
+	monitor.Owner = backend.Owner
 	err = backend.HandleMonitors(ctx, &monitor)
 	if err != nil {
+		r.setFailedStatus(ctx, lb, lbv1.ConditionSynced, reasonMonitorSyncFailed, err)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		span.End()
@@ -299,57 +482,114 @@ func (r *ExternalLoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl
 	// ----------------------------------------
 	// Handle IP Pools
 	// ----------------------------------------
-	pools := make([]lbv1.Pool, 0, len(lb.Spec.Ports))
-	for _, p := range lb.Spec.Ports {
-		// Create pool members based on nodes
-		var poolMembers []lbv1.PoolMember
-		for _, n := range nodes {
-			poolMember := &lbv1.PoolMember{
-				Node: n,
-				Port: p,
+	servicePorts := lb.Spec.GetServicePorts()
+	backend.RemovalGuard = controller.RemovalGuard{
+		MinMembers:        lb.Spec.MinMembers,
+		MaxRemovalPercent: lb.Spec.MaxRemovalPercent,
+	}
+	if lb.Spec.DrainPeriod != nil {
+		backend.DrainPeriod = lb.Spec.DrainPeriod.Duration
+	}
+	// The members draining in the previous provider are removed with its configuration
+	if !providerChanged(lb, lbBackend) {
+		backend.Draining = lb.Status.DrainingMembers
+	}
+	var refusedRemovals []string
+	pools := make([]lbv1.Pool, 0, len(servicePorts)*len(vipIPs))
+	for _, vipIP := range vipIPs {
+		for _, p := range servicePorts {
+			// Create pool members based on nodes
+			var poolMembers []lbv1.PoolMember
+			for _, n := range nodesByVIP[vipIP] {
+				poolMember := &lbv1.PoolMember{
+					Node: n,
+					Port: p.TargetPort,
+				}
+				poolMembers = append(poolMembers, *poolMember)
 			}
-			poolMembers = append(poolMembers, *poolMember)
-		}
 
-		// Create the pool object
-		pool := lbv1.Pool{
-			Name:    "Pool-" + lb.Name + "-" + strconv.Itoa(p),
-			Monitor: monitor.Name,
-			Members: poolMembers,
-		}
+			// Create the pool object
+			pool := lbv1.Pool{
+				Name:     names[controller.KindPool+"/"+portSuffix(p)+vipSuffix(lb, vipIP)],
+				Monitor:  monitor.Name,
+				Members:  poolMembers,
+				Protocol: p.Protocol,
+				Owner:    backend.Owner,
+			}
 
-		err := backend.HandlePool(ctx, &pool, &monitor)
-		if err != nil {
-			logger.Error(err, "unable to handle ExternalLoadBalancer IP pool")
-			span.RecordError(err)
-			span.SetStatus(codes.Error, err.Error())
-			span.End()
-			return ctrl.Result{}, err
+			err := backend.HandlePool(ctx, &pool, &monitor)
+			// A refused member removal keeps the previous members in the pool and
+			// doesn't stop the reconcile, the load balancer is marked as Degraded.
+			var refused *controller.MemberRemovalRefusedError
+			if errors.As(err, &refused) {
+				logger.Info("Pool member removal refused", "pool", pool.Name, "reason", refused.Reason)
+				r.Recorder.Eventf(lb, nil, corev1.EventTypeWarning, reasonRemovalRefused, "RemovePoolMembers", "%s", refused.Error())
+				refusedRemovals = append(refusedRemovals, refused.Error())
+				// The status records the members kept in the backend
+				pool.Members = refused.Members
+				err = nil
+			}
+			if err != nil {
+				logger.Error(err, "unable to handle ExternalLoadBalancer IP pool")
+				r.setFailedStatus(ctx, lb, lbv1.ConditionSynced, syncFailedReason(err, reasonPoolSyncFailed), err)
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				span.End()
+				return ctrl.Result{}, err
+			}
+			pools = append(pools, pool)
 		}
-		pools = append(pools, pool)
 	}
 
 	// ----------------------------------------
 	// Handle VIPs
 	// ----------------------------------------
-	vips := make([]lbv1.VIP, 0, len(lb.Spec.Ports))
-	for _, p := range lb.Spec.Ports {
-		vip := lbv1.VIP{
-			Name: "VIP-" + lb.Name + "-" + strconv.Itoa(p),
-			IP:   lb.Spec.Vip,
-			Pool: "Pool-" + lb.Name + "-" + strconv.Itoa(p),
-			Port: p,
-		}
+	vips := make([]lbv1.VIP, 0, len(servicePorts)*len(vipIPs))
+	for _, vipIP := range vipIPs {
+		for _, p := range servicePorts {
+			suffix := portSuffix(p) + vipSuffix(lb, vipIP)
+			vip := lbv1.VIP{
+				Name:     names[controller.KindVIP+"/"+suffix],
+				IP:       vipIP,
+				Pool:     names[controller.KindPool+"/"+suffix],
+				Port:     p.Port,
+				Protocol: p.Protocol,
+				Owner:    backend.Owner,
+			}
 
-		err := backend.HandleVIP(ctx, &vip)
-		if err != nil {
-			logger.Error(err, "unable to handle ExternalLoadBalancer VIP")
-			span.RecordError(err)
-			span.SetStatus(codes.Error, err.Error())
-			span.End()
-			return ctrl.Result{}, err
+			err := backend.HandleVIP(ctx, &vip)
+			if err != nil {
+				logger.Error(err, "unable to handle ExternalLoadBalancer VIP")
+				r.setFailedStatus(ctx, lb, lbv1.ConditionSynced, syncFailedReason(err, reasonVIPSyncFailed), err)
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				span.End()
+				return ctrl.Result{}, err
+			}
+			vips = append(vips, vip)
 		}
-		vips = append(vips, vip)
+	}
+
+	// ----------------------------------------
+	// Remove VIPs and Pools for ports no longer in the spec or renamed by the naming template
+	// ----------------------------------------
+	// The previous configuration is in the previous provider when it changed, it's
+	// removed there by cleanupPreviousProvider
+	previous := &lb.Status
+	if providerChanged(lb, lbBackend) {
+		previous = &lbv1.ExternalLoadBalancerStatus{}
+	}
+	err = backend.HandleStaleResources(ctx, previous, vips, pools)
+	if err == nil {
+		err = backend.HandleStaleMonitor(ctx, previous, &monitor)
+	}
+	if err != nil {
+		logger.Error(err, "unable to remove stale ExternalLoadBalancer resources")
+		r.setFailedStatus(ctx, lb, lbv1.ConditionSynced, reasonStaleCleanupFailed, err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+		return ctrl.Result{}, err
 	}
 
 	// ----------------------------------------
@@ -357,18 +597,45 @@ func (r *ExternalLoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl
 	// Depends on provider implementation
 	// ----------------------------------------
 	err = func(ctx context.Context) error {
-		_, span := otel.Tracer(name).Start(ctx, "Provider - Close")
+		ctx, span := otel.Tracer(name).Start(ctx, "Provider - Close")
 		defer span.End()
-		return backend.Provider.Close()
+		return backend.Provider.Close(ctx)
 	}(ctx)
 	if err != nil {
 		logger.Error(err, "unable to close the backend provider")
+		r.setFailedStatus(ctx, lb, lbv1.ConditionSynced, reasonCommitFailed, err)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		span.End()
 		return ctrl.Result{}, err
 	}
 
+	// ----------------------------------------
+	// Remove the configuration from the previous provider when the vendor or host changed.
+	// The status keeps the previous provider until it succeeds so it's retried.
+	// ----------------------------------------
+	var migrationChanges []string
+	if providerChanged(lb, lbBackend) {
+		migrationChanges, err = r.cleanupPreviousProvider(ctx, lb)
+		if err != nil {
+			logger.Error(err, "unable to remove the configuration from the previous provider", "provider", lb.Status.Provider.Vendor, "host", lb.Status.Provider.Host)
+			r.setFailedStatus(ctx, lb, lbv1.ConditionSynced, reasonMigrationFailed, err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			span.End()
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Keep the draining members only for the pools still configured
+	var drainingMembers []lbv1.DrainingMember
+	for _, d := range backend.Draining {
+		if controller.ContainsPool(pools, lbv1.Pool{Name: d.Pool}) {
+			drainingMembers = append(drainingMembers, d)
+		}
+	}
+	backend.Draining = drainingMembers
+
 	// ----------------------------------------
 	// Update ExternalLoadBalancer Status
 	// ----------------------------------------
@@ -378,15 +645,36 @@ func (r *ExternalLoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl
 		return r.Get(ctx, req.NamespacedName, lb)
 	}(ctx)
 
-	lb.Status = lbv1.ExternalLoadBalancerStatus{
-		VIPs:     vips,
-		Monitor:  monitor,
-		Ports:    lb.Spec.Ports,
-		Nodes:    nodes,
-		Pools:    pools,
-		Provider: lb.Spec.Provider,
-		Labels:   labels,
-		NumNodes: len(nodes),
+	previousProvider, migrated := lb.Status.Provider, providerChanged(lb, lbBackend)
+	if lb.Spec.Mode == lbv1.ModePlan {
+		// Nothing was applied so the status keeps the previously applied configuration
+		lb.Status.PlannedChanges = append(backend.PlannedChanges(), migrationChanges...)
+		lb.Status.Drift = backend.Drift
+		lb.Status.ObservedGeneration = lb.Generation
+		setPlannedConditions(lb)
+		if len(lb.Status.PlannedChanges) > 0 {
+			r.Recorder.Eventf(lb, nil, corev1.EventTypeNormal, reasonChangesPlanned, "Plan", "%s", planSummary(lb.Status.PlannedChanges))
+		}
+	} else {
+		lb.Status = lbv1.ExternalLoadBalancerStatus{
+			VIPs:               vips,
+			Monitor:            monitor,
+			Ports:              servicePortNumbers(lb),
+			Nodes:              nodes,
+			Pools:              pools,
+			Provider:           lbBackend,
+			Labels:             labels,
+			NumNodes:           len(nodes),
+			DrainingMembers:    drainingMembers,
+			Drift:              backend.Drift,
+			ObservedGeneration: lb.Generation,
+			Conditions:         lb.Status.Conditions,
+		}
+		setSucceededConditions(lb, refusedRemovals)
+		if migrated {
+			r.Recorder.Eventf(lb, nil, corev1.EventTypeNormal, reasonProviderMigrated, "Migrate", "Moved the load balancer configuration from %s %s to %s %s",
+				previousProvider.Vendor, previousProvider.Host, lbBackend.Vendor, lbBackend.Host)
+		}
 	}
 
 	err = func(ctx context.Context) error {
@@ -402,64 +690,6 @@ func (r *ExternalLoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl
 		return ctrl.Result{}, err
 	}
 
-	// ----------------------------------------
-	// Check if the ExternalLoadBalancer instance is marked to be deleted, which is
-	// indicated by the deletion timestamp being set.
-	// ----------------------------------------
-	isLoadBalancerMarkedToBeDeleted := func(ctx context.Context) bool {
-		_, span := otel.Tracer(name).Start(ctx, "GetDeletionTimestamp")
-		defer span.End()
-		return lb.GetDeletionTimestamp() != nil
-	}(ctx)
-
-	if isLoadBalancerMarkedToBeDeleted {
-
-		finalizers := func(ctx context.Context) []string {
-			_, span := otel.Tracer(name).Start(ctx, "GetFinalizers - Remove finalizer")
-			defer span.End()
-			return lb.GetFinalizers()
-		}(ctx)
-
-		if contains(finalizers, ExternalLoadBalancerFinalizer) {
-			// Run finalization logic for ExternalLoadBalancerFinalizer. If the
-			// finalization logic fails, don't remove the finalizer so
-			// that we can retry during the next reconciliation.
-
-			err = func(ctx context.Context) error {
-				_, span := otel.Tracer(name).Start(ctx, "finalizeLoadBalancer")
-				defer span.End()
-				return r.finalizeLoadBalancer(ctx, backend, lb)
-			}(ctx)
-			if err != nil {
-				span.RecordError(err)
-				span.SetStatus(codes.Error, err.Error())
-				span.End()
-				return ctrl.Result{}, err
-			}
-
-			// Remove ExternalLoadBalancerFinalizer. Once all finalizers have been
-			// removed, the object will be deleted.
-			func(ctx context.Context) {
-				_, span := otel.Tracer(name).Start(ctx, "RemoveFinalizer")
-				defer span.End()
-				controllerutil.RemoveFinalizer(lb, ExternalLoadBalancerFinalizer)
-			}(ctx)
-
-			err = func(ctx context.Context) error {
-				_, span := otel.Tracer(name).Start(ctx, "Update LoadBalancer after removing finalizer")
-				defer span.End()
-				return r.Update(ctx, lb)
-			}(ctx)
-			if err != nil {
-				span.RecordError(err)
-				span.SetStatus(codes.Error, err.Error())
-				span.End()
-				return ctrl.Result{}, err
-			}
-		}
-		return ctrl.Result{}, nil
-	}
-
 	// Add finalizer for this CR
 	finalizers := func(ctx context.Context) []string {
 		_, span := otel.Tracer(name).Start(ctx, "GetFinalizers - Add Finalizer")
@@ -481,8 +711,27 @@ func (r *ExternalLoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl
 		}
 	}
 
+	// Update the drift metrics
+	func(ctx context.Context) {
+		_, span := otel.Tracer(name).Start(ctx, "Metrics - Update metric_externallb_drift")
+		defer span.End()
+		span.SetAttributes(attribute.Int("metric.metric_externallb_drift.drift", len(backend.Drift)))
+		metric_externallb_drift.WithLabelValues(lb.Name, lb.Namespace).Set(float64(len(backend.Drift)))
+		metric_externallb_drift_detected.WithLabelValues(lb.Name, lb.Namespace).Add(float64(len(backend.Drift)))
+	}(ctx)
+
+	// Requeue to remove the draining members once their drain period ends
+	result := ctrl.Result{RequeueAfter: backend.NextDrain()}
+	if result.RequeueAfter > 0 {
+		logger.Info("Pool members draining, requeuing", "members", len(drainingMembers), "after", result.RequeueAfter)
+	}
+	// Requeue to resync the backend configuration and detect drift
+	if resync := r.resyncPeriod(lb); resync > 0 && (result.RequeueAfter == 0 || resync < result.RequeueAfter) {
+		result.RequeueAfter = resync
+	}
+
 	logger.Info("End of reconcile loop for ExternalLoadBalancer")
-	return ctrl.Result{}, nil
+	return result, nil
 }
 
 // SetupWithManager adds the reconciler in the Manager
@@ -504,8 +753,11 @@ func (r *ExternalLoadBalancerReconciler) SetupWithManager(mgr ctrl.Manager) erro
 					// Reconcile all ExternalLoadBalancers that match labels
 					lbToReconcile := make(map[string]bool)
 					for _, lb := range externalLoadBalancerList.Items {
-						labels := computeLabels(lb)
-						if containsLabels(node.Labels, labels) {
+						selector, err := nodeSelector(lb)
+						if err != nil {
+							continue
+						}
+						if selector.Matches(k8slabels.Set(node.Labels)) {
 							rec := reconcile.Request{
 								NamespacedName: types.NamespacedName{
 									Name:      lb.Name,
@@ -522,6 +774,25 @@ func (r *ExternalLoadBalancerReconciler) SetupWithManager(mgr ctrl.Manager) erro
 				return reconcileRequests
 			}),
 		).
+		// Watch LoadBalancerProvider spec changes to reconcile the ExternalLoadBalancers referencing them
+		Watches(&lbv1.LoadBalancerProvider{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+				externalLoadBalancerList := &lbv1.ExternalLoadBalancerList{}
+				if err := mgr.GetClient().List(ctx, externalLoadBalancerList); err != nil {
+					return []reconcile.Request{}
+				}
+				var reconcileRequests []reconcile.Request
+				for _, lb := range externalLoadBalancerList.Items {
+					if lb.Spec.ProviderRef == obj.GetName() {
+						reconcileRequests = append(reconcileRequests, reconcile.Request{
+							NamespacedName: types.NamespacedName{Name: lb.Name, Namespace: lb.Namespace},
+						})
+					}
+				}
+				return reconcileRequests
+			}),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		// Filter watched events to check only some fields on Node updates
 		WithEventFilter(predicate.Funcs{
 			UpdateFunc: func(e event.UpdateEvent) bool {
@@ -536,26 +807,135 @@ func (r *ExternalLoadBalancerReconciler) SetupWithManager(mgr ctrl.Manager) erro
 		Complete(r)
 }
 
-func (r *ExternalLoadBalancerReconciler) finalizeLoadBalancer(ctx context.Context, backend *controller.BackendController, lb *lbv1.ExternalLoadBalancer) error {
+// reconcileDelete runs the finalizer logic and removes the finalizer from
+// an ExternalLoadBalancer marked to be deleted.
+func (r *ExternalLoadBalancerReconciler) reconcileDelete(ctx context.Context, lb *lbv1.ExternalLoadBalancer) error {
+	var span trace.Span
+	ctx, span = otel.Tracer(name).Start(ctx, "reconcileDelete")
+	defer span.End()
+
+	finalizers := func(ctx context.Context) []string {
+		_, span := otel.Tracer(name).Start(ctx, "GetFinalizers - Remove finalizer")
+		defer span.End()
+		return lb.GetFinalizers()
+	}(ctx)
+
+	if !contains(finalizers, ExternalLoadBalancerFinalizer) {
+		return nil
+	}
+
+	// Run finalization logic for ExternalLoadBalancerFinalizer. If the
+	// finalization logic fails, don't remove the finalizer so
+	// that we can retry during the next reconciliation.
+	err := r.finalizeLoadBalancer(ctx, lb)
+	if err != nil {
+		// The deletion is retried until the backend is reachable, the status tells how to
+		// delete the instance without the cleanup
+		r.setFailedStatus(ctx, lb, lbv1.ConditionSynced, controller.ReasonCleanupFailed,
+			fmt.Errorf("unable to remove the configuration from the backend: %v. Set deletionpolicy to Retain or the %s annotation to \"true\" to delete the instance keeping the backend configuration", err, forceDeleteAnnotation))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	// Remove ExternalLoadBalancerFinalizer. Once all finalizers have been
+	// removed, the object will be deleted.
+	func(ctx context.Context) {
+		_, span := otel.Tracer(name).Start(ctx, "RemoveFinalizer")
+		defer span.End()
+		controllerutil.RemoveFinalizer(lb, ExternalLoadBalancerFinalizer)
+	}(ctx)
+
+	err = func(ctx context.Context) error {
+		_, span := otel.Tracer(name).Start(ctx, "Update LoadBalancer after removing finalizer")
+		defer span.End()
+		return r.Update(ctx, lb)
+	}(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+func (r *ExternalLoadBalancerReconciler) finalizeLoadBalancer(ctx context.Context, lb *lbv1.ExternalLoadBalancer) error {
 	// Create a span to track the finalizer of this load balancer
 	var span trace.Span
-	_, span = otel.Tracer(name).Start(ctx, "finalizeLoadBalancer")
+	ctx, span = otel.Tracer(name).Start(ctx, "finalizeLoadBalancer")
 	defer span.End()
 
 	reqLogger := log.FromContext(ctx)
 
+	// Nothing was configured in the backend for this load balancer
+	if lb.Status.Provider.Vendor == "" {
+		reqLogger.Info("ExternalLoadBalancer was never configured in the backend, skipping cleanup")
+		deleteLoadBalancerMetrics(lb)
+		return nil
+	}
+
+	// The backend configuration is kept running to be adopted by a new load balancer
+	if lb.Spec.DeletionPolicy == lbv1.DeletionPolicyRetain {
+		reqLogger.Info("ExternalLoadBalancer deletion policy is Retain, skipping backend cleanup")
+		span.SetAttributes(attribute.Bool("lb.cleanup.skipped", true))
+		r.Recorder.Eventf(lb, nil, corev1.EventTypeNormal, reasonCleanupRetained, "Cleanup", "Deletion policy is Retain, the load balancer configuration was kept in the backend")
+		deleteLoadBalancerMetrics(lb)
+		return nil
+	}
+
+	// The backend can't be cleaned up, like when it's unreachable or was decommissioned
+	if isForceDeleted(lb) {
+		reqLogger.Info("ExternalLoadBalancer force delete annotation is set, skipping backend cleanup", "annotation", forceDeleteAnnotation)
+		span.SetAttributes(attribute.Bool("lb.cleanup.skipped", true))
+		r.Recorder.Eventf(lb, nil, corev1.EventTypeWarning, reasonCleanupSkipped, "Cleanup", "Force delete annotation is set, the load balancer configuration was left in %s %s",
+			lb.Status.Provider.Vendor, lb.Status.Provider.Host)
+		deleteLoadBalancerMetrics(lb)
+		return nil
+	}
+
+	// ----------------------------------------
+	// Create Backend Provider
+	// ----------------------------------------
+	// The configuration recorded in the status lives in the provider it was applied to,
+	// which differs from the spec while a provider change is not complete
+	username, password, err := r.getProviderCredentials(ctx, lb, &lb.Status.Provider)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The secret is gone so there is no way to connect to the backend.
+			// Don't block the deletion of the load balancer.
+			reqLogger.Info("Provider credentials secret not found, skipping backend cleanup", "secret", lb.Status.Provider.Creds)
+			span.SetAttributes(attribute.Bool("lb.cleanup.skipped", true))
+			deleteLoadBalancerMetrics(lb)
+			return nil
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	backend, err := controller.CreateBackend(ctx, &lb.Status.Provider, username, password)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	backend.Recorder = r.Recorder
+	backend.Regarding = lb
+	// The plan mode only applies to the reconciliation. The configuration recorded in the
+	// status was applied before and is removed like in the other modes.
+
 	// ----------------------------------------
 	// Connect to Backend Provider
 	// ----------------------------------------
-	err := func(ctx context.Context) error {
-		_, span := otel.Tracer(name).Start(ctx, "Provider - Connect (for cleanup)")
+	err = func(ctx context.Context) error {
+		ctx, span := otel.Tracer(name).Start(ctx, "Provider - Connect (for cleanup)")
 		defer span.End()
-		return backend.Provider.Connect()
+		return backend.Provider.Connect(ctx)
 	}(ctx)
 	if err != nil {
+		r.Recorder.Eventf(lb, nil, corev1.EventTypeWarning, reasonConnectionFailed, "Cleanup", "%s", err.Error())
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		span.End()
 		return err
 	}
 
@@ -572,25 +952,157 @@ func (r *ExternalLoadBalancerReconciler) finalizeLoadBalancer(ctx context.Contex
 	// Depends on provider implementation
 	// ----------------------------------------
 	err = func(ctx context.Context) error {
-		_, span := otel.Tracer(name).Start(ctx, "Provider - Close (for cleanup)")
+		ctx, span := otel.Tracer(name).Start(ctx, "Provider - Close (for cleanup)")
 		defer span.End()
-		return backend.Provider.Close()
+		return backend.Provider.Close(ctx)
 	}(ctx)
 	if err != nil {
 		reqLogger.Error(err, "unable to close the backend provider")
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		span.End()
 		return err
 	}
 
 	// Delete metrics since the load balancer is gone
-	ports := strings.Trim(strings.Join(strings.Fields(fmt.Sprint(lb.Spec.Ports)), ","), "[]")
-	metric_externallb_nodes.DeleteLabelValues(lb.Name, lb.Namespace, lb.Spec.Type, lb.Spec.Vip, ports, lb.Spec.Provider.Vendor)
+	deleteLoadBalancerMetrics(lb)
+	r.Recorder.Eventf(lb, nil, corev1.EventTypeNormal, reasonCleanupCompleted, "Cleanup", "Removed the load balancer configuration from the backend")
 	reqLogger.Info("Successfully finalized ExternalLoadBalancer")
 	return nil
 }
 
+// cleanupPreviousProvider removes the load balancer configuration recorded in the status from the
+// previous provider after the vendor or host changed, connecting with its credentials secret.
+// It returns the changes planned in the previous provider in plan mode.
+func (r *ExternalLoadBalancerReconciler) cleanupPreviousProvider(ctx context.Context, lb *lbv1.ExternalLoadBalancer) ([]string, error) {
+	var span trace.Span
+	ctx, span = otel.Tracer(name).Start(ctx, "cleanupPreviousProvider")
+	span.SetAttributes(attribute.String("lb.name", lb.Name), attribute.String("lb.provider.previous", lb.Status.Provider.Vendor))
+	defer span.End()
+
+	reqLogger := log.FromContext(ctx)
+
+	// The previous configuration is kept running like when the load balancer is deleted
+	if lb.Spec.DeletionPolicy == lbv1.DeletionPolicyRetain {
+		reqLogger.Info("ExternalLoadBalancer deletion policy is Retain, keeping the configuration in the previous provider")
+		span.SetAttributes(attribute.Bool("lb.cleanup.skipped", true))
+		if lb.Spec.Mode != lbv1.ModePlan {
+			r.Recorder.Eventf(lb, nil, corev1.EventTypeNormal, reasonMigrationRetained, "Migrate", "Deletion policy is Retain, the load balancer configuration was kept in %s %s",
+				lb.Status.Provider.Vendor, lb.Status.Provider.Host)
+		}
+		return nil, nil
+	}
+
+	username, password, err := r.getProviderCredentials(ctx, lb, &lb.Status.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("previous provider credentials secret not found: %v", err)
+	}
+
+	backend, err := controller.CreateBackend(ctx, &lb.Status.Provider, username, password)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the previous provider: %v", err)
+	}
+	backend.Recorder = r.Recorder
+	backend.Regarding = lb
+	if lb.Spec.Mode == lbv1.ModePlan {
+		backend.Plan()
+	}
+
+	err = func(ctx context.Context) error {
+		ctx, span := otel.Tracer(name).Start(ctx, "Provider - Connect (previous provider)")
+		defer span.End()
+		return backend.Provider.Connect(ctx)
+	}(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to the previous provider: %v", err)
+	}
+
+	reqLogger.Info("Removing the configuration from the previous provider", "provider", lb.Status.Provider.Vendor, "host", lb.Status.Provider.Host)
+	err = backend.HandleCleanup(ctx, lb)
+	if err != nil {
+		return nil, fmt.Errorf("unable to remove the configuration from the previous provider: %v", err)
+	}
+
+	err = func(ctx context.Context) error {
+		ctx, span := otel.Tracer(name).Start(ctx, "Provider - Close (previous provider)")
+		defer span.End()
+		return backend.Provider.Close(ctx)
+	}(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to close the previous provider: %v", err)
+	}
+	return backend.PlannedChanges(), nil
+}
+
+// deleteLoadBalancerMetrics removes the metrics for a load balancer instance
+func deleteLoadBalancerMetrics(lb *lbv1.ExternalLoadBalancer) {
+	ports := strings.Trim(strings.Join(strings.Fields(fmt.Sprint(servicePortNumbers(lb))), ","), "[]")
+	metric_externallb_nodes.DeleteLabelValues(lb.Name, lb.Namespace, lb.Spec.Type, lb.Spec.Vip, ports, lb.Status.Provider.Vendor)
+	metric_externallb_drift.DeleteLabelValues(lb.Name, lb.Namespace)
+	metric_externallb_drift_detected.DeleteLabelValues(lb.Name, lb.Namespace)
+}
+
+// resyncPeriod returns the interval to resync the ExternalLoadBalancer backend configuration
+func (r *ExternalLoadBalancerReconciler) resyncPeriod(lb *lbv1.ExternalLoadBalancer) time.Duration {
+	if lb.Spec.ResyncPeriod != nil {
+		return lb.Spec.ResyncPeriod.Duration
+	}
+	return r.SyncPeriod
+}
+
+// resolveProvider returns the provider used by the load balancer, built from the referenced
+// LoadBalancerProvider when providerref is set. The returned reason is set in the status on errors.
+func (r *ExternalLoadBalancerReconciler) resolveProvider(ctx context.Context, lb *lbv1.ExternalLoadBalancer) (lbv1.Provider, string, error) {
+	_, span := otel.Tracer(name).Start(ctx, "Resolve Provider")
+	span.SetAttributes(attribute.String("lb.name", lb.Name), attribute.String("lb.providerref", lb.Spec.ProviderRef))
+	defer span.End()
+
+	if lb.Spec.ProviderRef == "" {
+		// Only the LoadBalancerProvider secrets can be in another namespace
+		if strings.Contains(lb.Spec.Provider.Creds, "/") {
+			return lbv1.Provider{}, reasonInvalidSpec, fmt.Errorf("provider creds must be a secret name in the ExternalLoadBalancer namespace")
+		}
+		return lb.Spec.Provider.WithDefaults(), "", nil
+	}
+
+	lbp := &lbv1.LoadBalancerProvider{}
+	if err := r.Get(ctx, types.NamespacedName{Name: lb.Spec.ProviderRef}, lbp); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return lbv1.Provider{}, reasonProviderNotFound, fmt.Errorf("unable to get LoadBalancerProvider %s: %v", lb.Spec.ProviderRef, err)
+	}
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: lb.Namespace}, ns); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return lbv1.Provider{}, reasonProviderNotAllowed, fmt.Errorf("unable to get namespace %s: %v", lb.Namespace, err)
+	}
+	allowed, err := lbp.AllowsNamespace(ns.Labels)
+	if err != nil {
+		return lbv1.Provider{}, reasonProviderNotAllowed, fmt.Errorf("invalid LoadBalancerProvider %s allowednamespaces: %v", lbp.Name, err)
+	}
+	if !allowed {
+		return lbv1.Provider{}, reasonProviderNotAllowed, fmt.Errorf("namespace %s is not allowed to use LoadBalancerProvider %s", lb.Namespace, lbp.Name)
+	}
+	return lbp.Provider(lb.Spec.Provider), "", nil
+}
+
+// getProviderCredentials reads the username and password from the provider credentials secret,
+// in the load balancer namespace unless the provider comes from a LoadBalancerProvider
+func (r *ExternalLoadBalancerReconciler) getProviderCredentials(ctx context.Context, lb *lbv1.ExternalLoadBalancer, provider *lbv1.Provider) (string, string, error) {
+	_, span := otel.Tracer(name).Start(ctx, "Get Backend Secret")
+	span.SetAttributes(attribute.String("lb.name", lb.Name), attribute.String("lb.provider", provider.Vendor), attribute.String("lb.provider.secret", provider.Creds))
+	defer span.End()
+
+	credsSecret := &corev1.Secret{}
+	err := r.Get(ctx, provider.CredsSecret(lb.Namespace), credsSecret)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", "", err
+	}
+	return string(credsSecret.Data["username"]), string(credsSecret.Data["password"]), nil
+}
+
 func (r *ExternalLoadBalancerReconciler) addFinalizer(ctx context.Context, m *lbv1.ExternalLoadBalancer) error {
 	var span trace.Span
 	_, span = otel.Tracer(name).Start(ctx, "finalizeLoadBalancer")
@@ -610,3 +1122,116 @@ func (r *ExternalLoadBalancerReconciler) addFinalizer(ctx context.Context, m *lb
 	}
 	return nil
 }
+
+// setFailedStatus records a failed reconcile in the ExternalLoadBalancer status conditions.
+// The failing condition, Synced and Ready are set to False and Degraded to True.
+func (r *ExternalLoadBalancerReconciler) setFailedStatus(ctx context.Context, lb *lbv1.ExternalLoadBalancer, conditionType string, reason string, err error) {
+	_, span := otel.Tracer(name).Start(ctx, "Update LoadBalancer Status conditions")
+	span.SetAttributes(attribute.String("lb.condition", conditionType), attribute.String("lb.condition.reason", reason))
+	defer span.End()
+
+	r.Recorder.Eventf(lb, nil, corev1.EventTypeWarning, reason, "Reconcile", "%s", err.Error())
+	for _, t := range []string{conditionType, lbv1.ConditionSynced, lbv1.ConditionReady} {
+		setCondition(lb, t, metav1.ConditionFalse, reason, err.Error())
+	}
+	setCondition(lb, lbv1.ConditionDegraded, metav1.ConditionTrue, reason, err.Error())
+	lb.Status.ObservedGeneration = lb.Generation
+
+	if uerr := r.Status().Update(ctx, lb); uerr != nil {
+		log.FromContext(ctx).Error(uerr, "unable to update ExternalLoadBalancer status conditions")
+		span.RecordError(uerr)
+		span.SetStatus(codes.Error, uerr.Error())
+	}
+}
+
+// syncFailedReason returns the NotOwned reason if the backend refused to modify an object
+// not owned by the load balancer or the reason otherwise
+func syncFailedReason(err error, reason string) string {
+	var notOwned *controller.NotOwnedError
+	if errors.As(err, &notOwned) {
+		return reasonNotOwned
+	}
+	return reason
+}
+
+// setPausedStatus sets the Paused condition and emits an event when the reconciliation
+// is paused or resumed
+func (r *ExternalLoadBalancerReconciler) setPausedStatus(ctx context.Context, lb *lbv1.ExternalLoadBalancer, paused bool) {
+	_, span := otel.Tracer(name).Start(ctx, "Update LoadBalancer Status paused condition")
+	span.SetAttributes(attribute.Bool("lb.paused", paused))
+	defer span.End()
+
+	if paused == meta.IsStatusConditionTrue(lb.Status.Conditions, lbv1.ConditionPaused) {
+		return
+	}
+	if paused {
+		message := fmt.Sprintf("Reconciliation paused by the %s annotation", pausedAnnotation)
+		setCondition(lb, lbv1.ConditionPaused, metav1.ConditionTrue, reasonPaused, message)
+		r.Recorder.Eventf(lb, nil, corev1.EventTypeNormal, reasonPaused, "Reconcile", "%s", message)
+	} else {
+		setCondition(lb, lbv1.ConditionPaused, metav1.ConditionFalse, reasonResumed, "Reconciliation resumed")
+		r.Recorder.Eventf(lb, nil, corev1.EventTypeNormal, reasonResumed, "Reconcile", "Reconciliation resumed, resyncing the backend configuration")
+	}
+
+	if err := r.Status().Update(ctx, lb); err != nil {
+		log.FromContext(ctx).Error(err, "unable to update ExternalLoadBalancer status conditions")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// setSucceededConditions sets the status conditions after a successful reconcile.
+// Pool member removals refused by the guard mark the load balancer as Degraded.
+func setSucceededConditions(lb *lbv1.ExternalLoadBalancer, refusedRemovals []string) {
+	setCondition(lb, lbv1.ConditionCredentialsValid, metav1.ConditionTrue, reasonReconciled, "Provider credentials secret found")
+	setCondition(lb, lbv1.ConditionBackendReachable, metav1.ConditionTrue, reasonReconciled, "Connected to the backend provider")
+	setCondition(lb, lbv1.ConditionSynced, metav1.ConditionTrue, reasonReconciled, "Monitor, pools and VIPs configured in the backend")
+	if lb.Status.NumNodes == 0 {
+		setCondition(lb, lbv1.ConditionReady, metav1.ConditionFalse, reasonNoReadyNodes, "No ready nodes match the load balancer")
+		setCondition(lb, lbv1.ConditionDegraded, metav1.ConditionTrue, reasonNoReadyNodes, "No ready nodes match the load balancer")
+	} else {
+		setCondition(lb, lbv1.ConditionReady, metav1.ConditionTrue, reasonReconciled, fmt.Sprintf("Load balancer configured with %d nodes", lb.Status.NumNodes))
+		setCondition(lb, lbv1.ConditionDegraded, metav1.ConditionFalse, reasonReconciled, "Load balancer configured")
+	}
+	if len(refusedRemovals) > 0 {
+		setCondition(lb, lbv1.ConditionDegraded, metav1.ConditionTrue, reasonRemovalRefused, strings.Join(refusedRemovals, "; "))
+	}
+	setDriftCondition(lb)
+}
+
+// setPlannedConditions sets the status conditions after a reconcile in plan mode.
+// The load balancer is only Synced and Ready when no changes are planned.
+func setPlannedConditions(lb *lbv1.ExternalLoadBalancer) {
+	setCondition(lb, lbv1.ConditionCredentialsValid, metav1.ConditionTrue, reasonReconciled, "Provider credentials secret found")
+	setCondition(lb, lbv1.ConditionBackendReachable, metav1.ConditionTrue, reasonReconciled, "Connected to the backend provider")
+	if len(lb.Status.PlannedChanges) > 0 {
+		message := planSummary(lb.Status.PlannedChanges)
+		setCondition(lb, lbv1.ConditionSynced, metav1.ConditionFalse, reasonChangesPlanned, message)
+		setCondition(lb, lbv1.ConditionReady, metav1.ConditionFalse, reasonChangesPlanned, message)
+	} else {
+		setCondition(lb, lbv1.ConditionSynced, metav1.ConditionTrue, reasonNoChangesPlanned, "Backend configuration matches the load balancer")
+		setCondition(lb, lbv1.ConditionReady, metav1.ConditionTrue, reasonNoChangesPlanned, "Backend configuration matches the load balancer")
+	}
+	setCondition(lb, lbv1.ConditionDegraded, metav1.ConditionFalse, reasonReconciled, "Load balancer planned")
+	setDriftCondition(lb)
+}
+
+// setDriftCondition sets the Drifted condition from the drift found in the last sync
+func setDriftCondition(lb *lbv1.ExternalLoadBalancer) {
+	if len(lb.Status.Drift) > 0 {
+		setCondition(lb, lbv1.ConditionDrifted, metav1.ConditionTrue, reasonDriftDetected, strings.Join(lb.Status.Drift, "; "))
+	} else {
+		setCondition(lb, lbv1.ConditionDrifted, metav1.ConditionFalse, reasonNoDrift, "Backend configuration matches the applied configuration")
+	}
+}
+
+// setCondition adds or updates a condition in the ExternalLoadBalancer status
+func setCondition(lb *lbv1.ExternalLoadBalancer, conditionType string, status metav1.ConditionStatus, reason string, message string) {
+	meta.SetStatusCondition(&lb.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: lb.Generation,
+	})
+}
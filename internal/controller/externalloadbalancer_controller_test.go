@@ -27,13 +27,20 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	lbv1 "github.com/carlosedp/lbconfig-operator/api/v1"
+	backend "github.com/carlosedp/lbconfig-operator/internal/controller/backend/backend_controller"
+	"github.com/carlosedp/lbconfig-operator/internal/controller/backend/dummy"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -91,6 +98,91 @@ var loadBalancer = &lbv1.ExternalLoadBalancer{
 	},
 }
 
+// guardPools keeps the pools configured by the PoolStore provider between reconciles
+var guardPools = struct {
+	sync.Mutex
+	pools map[string]*lbv1.Pool
+}{pools: map[string]*lbv1.Pool{}}
+
+// poolStoreProvider wraps the dummy provider keeping the configured pools and members
+type poolStoreProvider struct {
+	dummy.DummyProvider
+}
+
+func init() {
+	err := backend.RegisterProviderV2("PoolStore", func() backend.ProviderV2 { return new(poolStoreProvider) })
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (p *poolStoreProvider) GetPool(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
+	guardPools.Lock()
+	defer guardPools.Unlock()
+	if configured, ok := guardPools.pools[pool.Name]; ok {
+		return configured.DeepCopy(), nil
+	}
+	return nil, nil
+}
+
+func (p *poolStoreProvider) GetPoolMembers(ctx context.Context, pool *lbv1.Pool) (*lbv1.Pool, error) {
+	return p.GetPool(ctx, pool)
+}
+
+func (p *poolStoreProvider) CreatePool(ctx context.Context, pool *lbv1.Pool) error {
+	guardPools.Lock()
+	defer guardPools.Unlock()
+	guardPools.pools[pool.Name] = &lbv1.Pool{Name: pool.Name, Monitor: pool.Monitor, Owner: pool.Owner}
+	return nil
+}
+
+func (p *poolStoreProvider) DeletePool(ctx context.Context, pool *lbv1.Pool) error {
+	guardPools.Lock()
+	defer guardPools.Unlock()
+	delete(guardPools.pools, pool.Name)
+	return nil
+}
+
+func (p *poolStoreProvider) CreatePoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
+	guardPools.Lock()
+	defer guardPools.Unlock()
+	configured := guardPools.pools[pool.Name]
+	configured.Members = append(configured.Members, *m)
+	return nil
+}
+
+func (p *poolStoreProvider) DeletePoolMember(ctx context.Context, m *lbv1.PoolMember, pool *lbv1.Pool) error {
+	guardPools.Lock()
+	defer guardPools.Unlock()
+	configured := guardPools.pools[pool.Name]
+	configured.Members = slices.DeleteFunc(configured.Members, func(c lbv1.PoolMember) bool {
+		return c.Node.Host == m.Node.Host && c.Port == m.Port
+	})
+	return nil
+}
+
+// unreachable makes the connections of the Unreachable provider fail
+var unreachable atomic.Bool
+
+// unreachableProvider wraps the dummy provider failing to connect while unreachable is set
+type unreachableProvider struct {
+	dummy.DummyProvider
+}
+
+func init() {
+	err := backend.RegisterProviderV2("Unreachable", func() backend.ProviderV2 { return new(unreachableProvider) })
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (p *unreachableProvider) Connect(ctx context.Context) error {
+	if unreachable.Load() {
+		return fmt.Errorf("connection refused")
+	}
+	return p.DummyProvider.Connect(ctx)
+}
+
 var _ = Describe("ExternalLoadBalancer controller", Ordered, func() {
 	ctx := context.Background()
 	secretLookupKey := types.NamespacedName{Name: SecretName, Namespace: Namespace}
@@ -139,6 +231,13 @@ var _ = Describe("ExternalLoadBalancer controller", Ordered, func() {
 			return err
 		}, timeout, interval).Should(MatchError(MatchRegexp("undefined loadbalancer type or no nodelabels defined")))
 
+		By("By checking the failure is reported in the status conditions")
+		Eventually(func() *metav1.Condition {
+			_ = k8sClient.Get(ctx, types.NamespacedName{Name: lb3.Name, Namespace: Namespace}, lb3)
+			return meta.FindStatusCondition(lb3.Status.Conditions, lbv1.ConditionSynced)
+		}, timeout, interval).Should(And(Not(BeNil()), HaveField("Reason", "InvalidSpec"), HaveField("Status", metav1.ConditionFalse)))
+		Expect(meta.IsStatusConditionTrue(lb3.Status.Conditions, lbv1.ConditionDegraded)).To(BeTrue())
+
 		Expect(k8sClient.Delete(ctx, lb3)).Should(Succeed())
 		Consistently(func() (int, error) {
 			lblist := &lbv1.ExternalLoadBalancerList{}
@@ -197,6 +296,13 @@ var _ = Describe("ExternalLoadBalancer controller", Ordered, func() {
 		}
 		Expect(nodeAddresses).Should(ContainElement("1.1.1.1"))
 
+		By("By checking the ExternalLoadBalancer is Ready")
+		Eventually(func() bool {
+			_ = k8sClient.Get(ctx, loadBalancerLookupKey, loadBalancer)
+			return meta.IsStatusConditionTrue(loadBalancer.Status.Conditions, lbv1.ConditionReady)
+		}, timeout, interval).Should(BeTrue())
+		Expect(loadBalancer.Status.ObservedGeneration).To(Equal(loadBalancer.Generation))
+
 		By("By checking the ExternalLoadBalancer metric instance has 1 node")
 		metricsBody := getMetricsBody(metricsPort)
 		metricsOutput := fmt.Sprintf(`externallb_nodes{backend_vendor="%s",name="%s",namespace="%s",port="%s",type="%s",vip="%s"} %d`, loadBalancer.Spec.Provider.Vendor, loadBalancer.Name, Namespace, strconv.Itoa(loadBalancer.Spec.Provider.Port), loadBalancer.Spec.Type, loadBalancer.Spec.Vip, 1)
@@ -303,6 +409,29 @@ var _ = Describe("ExternalLoadBalancer controller", Ordered, func() {
 		Expect(metricsBody).To(ContainSubstring(metricsOutput))
 	})
 
+	It("should remove a cordoned master node from load balancer instance", func() {
+		By("By cordoning one Master Node")
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "master-node-2"}, node)).Should(Succeed())
+		node.Spec.Unschedulable = true
+		Expect(k8sClient.Update(ctx, node)).Should(Succeed())
+
+		By("By checking the ExternalLoadBalancer has one Node")
+		Eventually(func() ([]lbv1.Node, error) {
+			err := k8sClient.Get(ctx, loadBalancerLookupKey, loadBalancer)
+			return loadBalancer.Status.Nodes, err
+		}, timeout, interval).Should(HaveLen(1))
+		Expect(loadBalancer.Status.Nodes[0].Name).ShouldNot(Equal("master-node-2"))
+
+		By("By uncordoning the Master Node")
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "master-node-2"}, node)).Should(Succeed())
+		node.Spec.Unschedulable = false
+		Expect(k8sClient.Update(ctx, node)).Should(Succeed())
+		Eventually(func() ([]lbv1.Node, error) {
+			err := k8sClient.Get(ctx, loadBalancerLookupKey, loadBalancer)
+			return loadBalancer.Status.Nodes, err
+		}, timeout, interval).Should(HaveLen(2))
+	})
+
 	It("should remove a master node from load balancer instance", func() {
 		By("By removing one Master Node")
 		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "master-node-1"}, node)).Should(Succeed())
@@ -331,6 +460,197 @@ var _ = Describe("ExternalLoadBalancer controller", Ordered, func() {
 		Expect(metricsBody).To(ContainSubstring(metricsOutput))
 	})
 
+	It("should remove VIPs and pools for ports removed from the spec", func() {
+		By("By adding a port to the instance")
+		Expect(k8sClient.Get(ctx, loadBalancerLookupKey, loadBalancer)).Should(Succeed())
+		loadBalancer.Spec.Ports = []int{443, 6443}
+		Expect(k8sClient.Update(ctx, loadBalancer)).Should(Succeed())
+		Eventually(func() (int, error) {
+			err := k8sClient.Get(ctx, loadBalancerLookupKey, loadBalancer)
+			if err != nil {
+				return 0, err
+			}
+			return len(loadBalancer.Status.VIPs), nil
+		}, timeout, interval).Should(Equal(2))
+
+		By("By removing the port from the instance")
+		loadBalancer.Spec.Ports = []int{443}
+		Expect(k8sClient.Update(ctx, loadBalancer)).Should(Succeed())
+		Eventually(func() ([]lbv1.Pool, error) {
+			err := k8sClient.Get(ctx, loadBalancerLookupKey, loadBalancer)
+			if err != nil {
+				return nil, err
+			}
+			return loadBalancer.Status.Pools, nil
+		}, timeout, interval).Should(HaveLen(1))
+		Expect(loadBalancer.Status.VIPs).Should(HaveLen(1))
+		Expect(loadBalancer.Status.VIPs[0].Name).Should(Equal("VIP-" + Namespace + "-" + testLoadBalancerName + "-443"))
+	})
+
+	It("should keep the members refused by the removal guard in the status", func() {
+		By("By creating an instance with a minimum of two members")
+		labels := map[string]string{"guard-test": ""}
+		Expect(k8sClient.Create(ctx, createReadyNode("guard-node-1", labels, "3.3.3.1"))).Should(Succeed())
+		Expect(k8sClient.Create(ctx, createReadyNode("guard-node-2", labels, "3.3.3.2"))).Should(Succeed())
+		lb := loadBalancer.DeepCopy()
+		lb.ObjectMeta = metav1.ObjectMeta{Name: "test-load-balancer-guard", Namespace: Namespace}
+		lb.Spec.Type = ""
+		lb.Spec.NodeLabels = labels
+		lb.Spec.MinMembers = 2
+		lb.Spec.Provider.Vendor = "PoolStore"
+		lb.Status = lbv1.ExternalLoadBalancerStatus{}
+		lookupKey := types.NamespacedName{Name: lb.Name, Namespace: Namespace}
+		Expect(k8sClient.Create(ctx, lb)).Should(Succeed())
+		Eventually(func() bool {
+			_ = k8sClient.Get(ctx, lookupKey, lb)
+			return meta.IsStatusConditionTrue(lb.Status.Conditions, lbv1.ConditionReady)
+		}, timeout, interval).Should(BeTrue())
+		Expect(lb.Status.Pools).Should(HaveLen(1))
+		Expect(lb.Status.Pools[0].Members).Should(HaveLen(2))
+
+		By("By removing one of the nodes")
+		Expect(k8sClient.Delete(ctx, createReadyNode("guard-node-2", labels, "3.3.3.2"))).Should(Succeed())
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: lookupKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("By checking the pool status keeps the member not removed")
+		Eventually(func() bool {
+			_ = k8sClient.Get(ctx, lookupKey, lb)
+			return meta.IsStatusConditionTrue(lb.Status.Conditions, lbv1.ConditionDegraded)
+		}, timeout, interval).Should(BeTrue())
+		Expect(lb.Status.Nodes).Should(HaveLen(1))
+		Expect(lb.Status.Pools[0].Members).Should(HaveLen(2))
+		Expect(lb.Status.Pools[0].Members).Should(ContainElement(HaveField("Node.Host", "3.3.3.2")))
+
+		By("By removing the instance and node")
+		Expect(k8sClient.Delete(ctx, lb)).Should(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, lookupKey, lb))
+		}, timeout, interval).Should(BeTrue())
+		Expect(k8sClient.Delete(ctx, createReadyNode("guard-node-1", labels, "3.3.3.1"))).Should(Succeed())
+	})
+
+	It("should clean up the backend when an instance in plan mode is deleted", func() {
+		By("By creating an instance and switching it to plan mode")
+		lb := loadBalancer.DeepCopy()
+		lb.ObjectMeta = metav1.ObjectMeta{Name: "test-load-balancer-plan", Namespace: Namespace}
+		lb.Spec.Provider.Vendor = "PoolStore"
+		lb.Status = lbv1.ExternalLoadBalancerStatus{}
+		lookupKey := types.NamespacedName{Name: lb.Name, Namespace: Namespace}
+		poolName := "Pool-" + Namespace + "-" + lb.Name + "-443"
+		Expect(k8sClient.Create(ctx, lb)).Should(Succeed())
+		Eventually(func() bool {
+			_ = k8sClient.Get(ctx, lookupKey, lb)
+			return meta.IsStatusConditionTrue(lb.Status.Conditions, lbv1.ConditionReady)
+		}, timeout, interval).Should(BeTrue())
+		Expect(guardPools.pools).Should(HaveKey(poolName))
+		lb.Spec.Mode = lbv1.ModePlan
+		Expect(k8sClient.Update(ctx, lb)).Should(Succeed())
+
+		By("By removing the instance")
+		Expect(k8sClient.Delete(ctx, lb)).Should(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, lookupKey, lb))
+		}, timeout, interval).Should(BeTrue())
+
+		By("By checking the pool was removed from the backend")
+		guardPools.Lock()
+		defer guardPools.Unlock()
+		Expect(guardPools.pools).ShouldNot(HaveKey(poolName))
+	})
+
+	It("should clean up the backend when a paused instance is deleted", func() {
+		By("By creating an instance and pausing it")
+		lb := loadBalancer.DeepCopy()
+		lb.ObjectMeta = metav1.ObjectMeta{Name: "test-load-balancer-paused", Namespace: Namespace}
+		lb.Spec.Provider.Vendor = "PoolStore"
+		lb.Status = lbv1.ExternalLoadBalancerStatus{}
+		lookupKey := types.NamespacedName{Name: lb.Name, Namespace: Namespace}
+		poolName := "Pool-" + Namespace + "-" + lb.Name + "-443"
+		Expect(k8sClient.Create(ctx, lb)).Should(Succeed())
+		Eventually(func() bool {
+			_ = k8sClient.Get(ctx, lookupKey, lb)
+			return meta.IsStatusConditionTrue(lb.Status.Conditions, lbv1.ConditionReady)
+		}, timeout, interval).Should(BeTrue())
+		lb.Annotations = map[string]string{pausedAnnotation: "true"}
+		Expect(k8sClient.Update(ctx, lb)).Should(Succeed())
+		Eventually(func() bool {
+			_ = k8sClient.Get(ctx, lookupKey, lb)
+			return meta.IsStatusConditionTrue(lb.Status.Conditions, lbv1.ConditionPaused)
+		}, timeout, interval).Should(BeTrue())
+
+		By("By removing the instance without resuming it")
+		Expect(k8sClient.Delete(ctx, lb)).Should(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, lookupKey, lb))
+		}, timeout, interval).Should(BeTrue())
+
+		By("By checking the pool was removed from the backend")
+		guardPools.Lock()
+		defer guardPools.Unlock()
+		Expect(guardPools.pools).ShouldNot(HaveKey(poolName))
+	})
+
+	It("should delete an instance with an unreachable backend with the force delete annotation", func() {
+		By("By creating an instance and making its backend unreachable")
+		lb := loadBalancer.DeepCopy()
+		lb.ObjectMeta = metav1.ObjectMeta{Name: "test-load-balancer-unreachable", Namespace: Namespace}
+		lb.Spec.Provider.Vendor = "Unreachable"
+		lb.Status = lbv1.ExternalLoadBalancerStatus{}
+		lookupKey := types.NamespacedName{Name: lb.Name, Namespace: Namespace}
+		Expect(k8sClient.Create(ctx, lb)).Should(Succeed())
+		Eventually(func() bool {
+			_ = k8sClient.Get(ctx, lookupKey, lb)
+			return meta.IsStatusConditionTrue(lb.Status.Conditions, lbv1.ConditionReady)
+		}, timeout, interval).Should(BeTrue())
+		unreachable.Store(true)
+		defer unreachable.Store(false)
+
+		By("By checking the failed cleanup tells how to delete the instance")
+		Expect(k8sClient.Delete(ctx, lb)).Should(Succeed())
+		Eventually(func() string {
+			_ = k8sClient.Get(ctx, lookupKey, lb)
+			c := meta.FindStatusCondition(lb.Status.Conditions, lbv1.ConditionSynced)
+			if c == nil || c.Reason != backend.ReasonCleanupFailed {
+				return ""
+			}
+			return c.Message
+		}, timeout, interval).Should(ContainSubstring(forceDeleteAnnotation))
+		Expect(lb.GetFinalizers()).Should(ContainElement(ExternalLoadBalancerFinalizer))
+
+		By("By setting the force delete annotation")
+		lb.Annotations = map[string]string{forceDeleteAnnotation: "true"}
+		Expect(k8sClient.Update(ctx, lb)).Should(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, lookupKey, lb))
+		}, timeout, interval).Should(BeTrue())
+	})
+
+	It("should delete an instance whose credentials secret is gone", func() {
+		By("By creating an instance with its own secret")
+		secret := credsSecret.DeepCopy()
+		secret.ObjectMeta = metav1.ObjectMeta{Name: "temporary-creds", Namespace: Namespace}
+		Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+		lb := loadBalancer.DeepCopy()
+		lb.ObjectMeta = metav1.ObjectMeta{Name: "test-load-balancer-nosecret", Namespace: Namespace}
+		lb.Spec.Provider.Creds = secret.Name
+		lb.Status = lbv1.ExternalLoadBalancerStatus{}
+		lookupKey := types.NamespacedName{Name: lb.Name, Namespace: Namespace}
+		Expect(k8sClient.Create(ctx, lb)).Should(Succeed())
+		Eventually(func() []string {
+			_ = k8sClient.Get(ctx, lookupKey, lb)
+			return lb.GetFinalizers()
+		}, timeout, interval).Should(ContainElement(ExternalLoadBalancerFinalizer))
+
+		By("By removing the secret and then the instance")
+		Expect(k8sClient.Delete(ctx, secret)).Should(Succeed())
+		Expect(k8sClient.Delete(ctx, lb)).Should(Succeed())
+		Eventually(func() bool {
+			err := k8sClient.Get(ctx, lookupKey, lb)
+			return errors.IsNotFound(err)
+		}, timeout, interval).Should(BeTrue())
+	})
+
 	It("should delete the external load balancer instance", func() {
 		By("By removing the instance")
 		Expect(k8sClient.Delete(ctx, loadBalancer)).Should(Succeed())
@@ -0,0 +1,159 @@
+/*
+MIT License
+
+Copyright (c) 2022 Carlos Eduardo de Paula
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	lbv1 "github.com/carlosedp/lbconfig-operator/api/v1"
+	controller "github.com/carlosedp/lbconfig-operator/internal/controller/backend/backend_controller"
+)
+
+// Reasons used in the LoadBalancerProvider Reachable condition
+const (
+	reasonProbeSucceeded    = "Connected"
+	reasonProbeNotSupported = "ProbeNotSupported"
+)
+
+// LoadBalancerProviderReconciler reconciles a LoadBalancerProvider object, checking the
+// Load Balancer API is reachable with the credentials and reporting its version
+type LoadBalancerProviderReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// ProbePeriod is the interval the Load Balancer API is checked, zero disables the periodic check
+	ProbePeriod time.Duration
+}
+
+// +kubebuilder:rbac:groups=lb.lbconfig.carlosedp.com,resources=loadbalancerproviders,verbs=get;list;watch
+// +kubebuilder:rbac:groups=lb.lbconfig.carlosedp.com,resources=loadbalancerproviders/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Reconcile our LoadBalancerProvider object
+func (r *LoadBalancerProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var span trace.Span
+	ctx, span = otel.Tracer(name).Start(ctx, "Reconcile LoadBalancerProvider")
+	defer span.End()
+
+	logger := log.FromContext(ctx)
+
+	lbp := &lbv1.LoadBalancerProvider{}
+	if err := r.Get(ctx, req.NamespacedName, lbp); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("LoadBalancerProvider resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get LoadBalancerProvider")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return ctrl.Result{}, err
+	}
+	span.SetAttributes(attribute.String("lbp.name", lbp.Name), attribute.String("lbp.provider", lbp.Spec.Vendor))
+
+	status := lbp.Status.DeepCopy()
+	version, reason, err := r.probe(ctx, lbp)
+	switch {
+	case errors.Is(err, controller.ErrVersionNotSupported):
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type: lbv1.ConditionReachable, Status: metav1.ConditionUnknown, Reason: reasonProbeNotSupported, Message: err.Error(),
+			ObservedGeneration: lbp.Generation,
+		})
+	case err != nil:
+		logger.Info("Load Balancer API is not reachable", "reason", reason, "error", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type: lbv1.ConditionReachable, Status: metav1.ConditionFalse, Reason: reason, Message: err.Error(),
+			ObservedGeneration: lbp.Generation,
+		})
+	default:
+		status.Version = version
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type: lbv1.ConditionReachable, Status: metav1.ConditionTrue, Reason: reasonProbeSucceeded, Message: "Connected to the Load Balancer API",
+			ObservedGeneration: lbp.Generation,
+		})
+	}
+	status.ObservedGeneration = lbp.Generation
+
+	// Only update the status when it changed so the periodic probe doesn't generate writes
+	if !equality.Semantic.DeepEqual(&lbp.Status, status) {
+		lbp.Status = *status
+		if err := r.Status().Update(ctx, lbp); err != nil {
+			logger.Error(err, "unable to update LoadBalancerProvider status")
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: r.ProbePeriod}, nil
+}
+
+// probe returns the Load Balancer version reading it from the API with the provider credentials.
+// The returned reason is set in the Reachable condition on errors.
+func (r *LoadBalancerProviderReconciler) probe(ctx context.Context, lbp *lbv1.LoadBalancerProvider) (string, string, error) {
+	provider := lbp.Provider(lbv1.Provider{})
+
+	credsSecret := &corev1.Secret{}
+	if err := r.Get(ctx, provider.CredsSecret(""), credsSecret); err != nil {
+		return "", reasonSecretNotFound, err
+	}
+
+	backend, err := controller.CreateBackend(ctx, &provider, string(credsSecret.Data["username"]), string(credsSecret.Data["password"]))
+	if err != nil {
+		return "", reasonProviderCreateFailed, err
+	}
+	// Connect isn't called since it could open a transaction in the backend
+	version, err := backend.Version(ctx)
+	if err != nil {
+		return "", reasonConnectionFailed, err
+	}
+	return version, "", nil
+}
+
+// SetupWithManager adds the reconciler in the Manager
+func (r *LoadBalancerProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// The status updates are ignored, the API is checked again after the probe period
+		For(&lbv1.LoadBalancerProvider{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
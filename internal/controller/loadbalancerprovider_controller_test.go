@@ -0,0 +1,107 @@
+/*
+MIT License
+
+Copyright (c) 2022 Carlos Eduardo de Paula
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	lbv1 "github.com/carlosedp/lbconfig-operator/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("LoadBalancerProvider controller", Ordered, func() {
+	ctx := context.Background()
+	var pr *LoadBalancerProviderReconciler
+
+	providerSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "provider-creds",
+			Namespace: Namespace,
+		},
+		Data: map[string][]byte{
+			"username": []byte("testuser"),
+			"password": []byte("testpassword"),
+		},
+	}
+	lbp := &lbv1.LoadBalancerProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-provider",
+		},
+		Spec: lbv1.LoadBalancerProviderSpec{
+			Vendor: "Dummy",
+			Host:   "http://1.2.3.4",
+			Port:   443,
+			Creds: lbv1.SecretReference{
+				Name:      providerSecret.Name,
+				Namespace: Namespace,
+			},
+		},
+	}
+	lbpLookupKey := types.NamespacedName{Name: lbp.Name}
+
+	BeforeAll(func() {
+		pr = &LoadBalancerProviderReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		Expect(k8sClient.Create(ctx, providerSecret)).Should(Succeed())
+		Expect(k8sClient.Create(ctx, lbp)).Should(Succeed())
+	})
+
+	AfterAll(func() {
+		Expect(k8sClient.Delete(ctx, lbp)).Should(Succeed())
+		Expect(k8sClient.Delete(ctx, providerSecret)).Should(Succeed())
+	})
+
+	It("Should report the Load Balancer as reachable with its version", func() {
+		_, err := pr.Reconcile(ctx, reconcile.Request{NamespacedName: lbpLookupKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		createdLBP := &lbv1.LoadBalancerProvider{}
+		Expect(k8sClient.Get(ctx, lbpLookupKey, createdLBP)).Should(Succeed())
+		Expect(createdLBP.Status.Version).To(Equal("dummy"))
+		Expect(createdLBP.Status.ObservedGeneration).To(Equal(createdLBP.Generation))
+		Expect(meta.IsStatusConditionTrue(createdLBP.Status.Conditions, lbv1.ConditionReachable)).To(BeTrue())
+	})
+
+	It("Should report the Load Balancer as not reachable without the credentials secret", func() {
+		createdLBP := &lbv1.LoadBalancerProvider{}
+		Expect(k8sClient.Get(ctx, lbpLookupKey, createdLBP)).Should(Succeed())
+		createdLBP.Spec.Creds.Name = "missing-creds"
+		Expect(k8sClient.Update(ctx, createdLBP)).Should(Succeed())
+
+		_, err := pr.Reconcile(ctx, reconcile.Request{NamespacedName: lbpLookupKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(k8sClient.Get(ctx, lbpLookupKey, createdLBP)).Should(Succeed())
+		cond := meta.FindStatusCondition(createdLBP.Status.Conditions, lbv1.ConditionReachable)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(reasonSecretNotFound))
+	})
+})
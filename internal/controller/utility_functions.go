@@ -25,11 +25,18 @@ SOFTWARE.
 package controllers
 
 import (
+	"fmt"
+	"net"
 	"reflect"
+	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	lbv1 "github.com/carlosedp/lbconfig-operator/api/v1"
+	controller "github.com/carlosedp/lbconfig-operator/internal/controller/backend/backend_controller"
 )
 
 // -----------------------------------------
@@ -46,12 +53,20 @@ func contains(list []string, s string) bool {
 	return false
 }
 
+// isPaused checks if the ExternalLoadBalancer reconciliation is paused by the annotation
+func isPaused(lb *lbv1.ExternalLoadBalancer) bool {
+	return lb.GetAnnotations()[pausedAnnotation] == "true"
+}
+
+// isForceDeleted checks if the ExternalLoadBalancer is deleted without the backend cleanup by the annotation
+func isForceDeleted(lb *lbv1.ExternalLoadBalancer) bool {
+	return lb.GetAnnotations()[forceDeleteAnnotation] == "true"
+}
+
 // hasNodeChanged checks two instances of node and compares if some fields have changed
 func hasNodeChanged(o *corev1.Node, n *corev1.Node) bool {
 	var oldCond corev1.ConditionStatus
 	var newCond corev1.ConditionStatus
-	var oldIP string
-	var newIP string
 
 	for _, cond := range o.Status.Conditions {
 		if cond.Type == readyCondition {
@@ -63,35 +78,216 @@ func hasNodeChanged(o *corev1.Node, n *corev1.Node) bool {
 			newCond = cond.Status
 		}
 	}
-	oldIP = getNodeIP(o)
-	newIP = getNodeIP(n)
-
-	if (oldCond == newCond) && (oldIP == newIP) && reflect.DeepEqual(o.Labels, n.Labels) {
+	if (oldCond == newCond) && reflect.DeepEqual(o.Status.Addresses, n.Status.Addresses) &&
+		(o.Annotations[nodeAddressAnnotation] == n.Annotations[nodeAddressAnnotation]) && reflect.DeepEqual(o.Labels, n.Labels) &&
+		(o.Spec.Unschedulable == n.Spec.Unschedulable) && reflect.DeepEqual(o.Spec.Taints, n.Spec.Taints) {
 		return false
 	}
 	return true
 }
 
-func getNodeIP(node *corev1.Node) string {
+// nodeExclusionReason returns why a node must be kept out of the LoadBalancer pools or
+// an empty string if the node is eligible. Cordoned nodes, nodes with the
+// exclude-from-external-load-balancers label and nodes with one of the taints are excluded.
+func nodeExclusionReason(node *corev1.Node, taints []lbv1.NodeTaint) string {
+	if node.Spec.Unschedulable {
+		return "node is cordoned"
+	}
+	if _, ok := node.Labels[corev1.LabelNodeExcludeBalancers]; ok {
+		return "node has the " + corev1.LabelNodeExcludeBalancers + " label"
+	}
+	for _, t := range node.Spec.Taints {
+		for _, e := range taints {
+			if t.Key == e.Key && (e.Value == "" || t.Value == e.Value) && (e.Effect == "" || string(t.Effect) == e.Effect) {
+				return "node has the " + t.ToString() + " taint"
+			}
+		}
+	}
+	return ""
+}
+
+// nodeAddressPolicy selects the node address used for the pool members
+type nodeAddressPolicy struct {
+	types []corev1.NodeAddressType
+	cidrs []*net.IPNet
+}
+
+// newNodeAddressPolicy builds the node address policy from the ExternalLoadBalancer spec.
+// The default policy prefers ExternalIP over InternalIP.
+func newNodeAddressPolicy(spec *lbv1.NodeAddress) (nodeAddressPolicy, error) {
+	policy := nodeAddressPolicy{types: []corev1.NodeAddressType{corev1.NodeExternalIP, corev1.NodeInternalIP}}
+	if spec == nil {
+		return policy, nil
+	}
+	if len(spec.Types) > 0 {
+		policy.types = make([]corev1.NodeAddressType, 0, len(spec.Types))
+		for _, t := range spec.Types {
+			policy.types = append(policy.types, corev1.NodeAddressType(t))
+		}
+	}
+	for _, c := range spec.CIDRs {
+		_, cidr, err := net.ParseCIDR(c)
+		if err != nil {
+			return policy, err
+		}
+		policy.cidrs = append(policy.cidrs, cidr)
+	}
+	return policy, nil
+}
+
+// matches checks if the address is from the requested IP family and inside the policy CIDRs.
+// Hostnames match any IP family but not the CIDRs.
+func (p nodeAddressPolicy) matches(address string, ipv6 bool) bool {
+	ip := net.ParseIP(strings.Split(address, "%")[0])
+	if ip == nil {
+		return len(p.cidrs) == 0
+	}
+	if controller.IsIPv6(address) != ipv6 {
+		return false
+	}
+	if len(p.cidrs) == 0 {
+		return true
+	}
+	for _, cidr := range p.cidrs {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// getNodeIP returns the node address from the requested IP family following the address type priority
+// of the policy. The addresses in the nodeAddressAnnotation override the node status addresses.
+func getNodeIP(node *corev1.Node, ipv6 bool, policy nodeAddressPolicy) string {
 	var nodeReady = false
-	var nodeIPs = make(map[corev1.NodeAddressType]string)
-	var IP string
 	for _, cond := range node.Status.Conditions {
 		if cond.Type == "Ready" && cond.Status == "True" {
 			nodeReady = true
 		}
 	}
-	for _, addr := range node.Status.Addresses {
-		if nodeReady {
-			nodeIPs[addr.Type] = addr.Address
+	if !nodeReady {
+		return ""
+	}
+	if override, ok := node.Annotations[nodeAddressAnnotation]; ok {
+		for _, addr := range strings.Split(override, ",") {
+			addr = strings.TrimSpace(addr)
+			if addr != "" && net.ParseIP(addr) != nil && controller.IsIPv6(addr) == ipv6 {
+				return addr
+			}
 		}
+		return ""
 	}
-	if val, ok := nodeIPs["ExternalIP"]; ok {
-		IP = val
-	} else {
-		IP = nodeIPs["InternalIP"]
+	for _, t := range policy.types {
+		for _, addr := range node.Status.Addresses {
+			if addr.Type == t && policy.matches(addr.Address, ipv6) {
+				return addr.Address
+			}
+		}
 	}
-	return IP
+	return ""
+}
+
+// maxPlanSummaryChanges is the number of planned changes listed in the plan summary
+const maxPlanSummaryChanges = 10
+
+// planSummary returns a message with the number of planned changes and the first ones
+func planSummary(changes []string) string {
+	if len(changes) == 0 {
+		return "No changes planned"
+	}
+	summary := fmt.Sprintf("%d changes planned: %s", len(changes), strings.Join(changes[:min(len(changes), maxPlanSummaryChanges)], "; "))
+	if len(changes) > maxPlanSummaryChanges {
+		summary += fmt.Sprintf(" and %d more", len(changes)-maxPlanSummaryChanges)
+	}
+	return summary
+}
+
+// portSuffix returns the name suffix for the resources created for a port.
+// UDP ports are suffixed with the protocol so they don't clash with a TCP port with the same number.
+func portSuffix(p lbv1.ServicePort) string {
+	if p.Protocol == lbv1.ProtocolUDP {
+		return strconv.Itoa(p.Port) + "-udp"
+	}
+	return strconv.Itoa(p.Port)
+}
+
+// servicePortNumbers returns the ports the VIPs listen to
+func servicePortNumbers(lb *lbv1.ExternalLoadBalancer) []int {
+	servicePorts := lb.Spec.GetServicePorts()
+	ports := make([]int, 0, len(servicePorts))
+	for _, p := range servicePorts {
+		ports = append(ports, p.Port)
+	}
+	return ports
+}
+
+// vipAddresses returns the VIP addresses for the ExternalLoadBalancer, the primary VIP first
+func vipAddresses(lb *lbv1.ExternalLoadBalancer) []string {
+	addresses := []string{lb.Spec.Vip}
+	if lb.Spec.SecondaryVip != "" {
+		addresses = append(addresses, lb.Spec.SecondaryVip)
+	}
+	return addresses
+}
+
+// vipSuffix returns the name suffix for the resources created for a VIP address.
+// The primary VIP has no suffix so existing resources keep their names.
+func vipSuffix(lb *lbv1.ExternalLoadBalancer, address string) string {
+	if address == lb.Spec.Vip {
+		return ""
+	}
+	if controller.IsIPv6(address) {
+		return "-ipv6"
+	}
+	return "-ipv4"
+}
+
+// objectNames renders the backend object names of the ExternalLoadBalancer with the naming template,
+// keyed by the kind and the name suffix like "Pool/443-ipv6", so a template failing with the
+// instance data is found before any change is made to the backend
+func objectNames(lb *lbv1.ExternalLoadBalancer, b *controller.BackendController, namingTemplate string, clusterID string) (map[string]string, error) {
+	namer, err := b.Namer(namingTemplate)
+	if err != nil {
+		return nil, err
+	}
+	data := []controller.NameData{{Kind: controller.KindMonitor}}
+	for _, vipIP := range vipAddresses(lb) {
+		for _, p := range lb.Spec.GetServicePorts() {
+			suffix := portSuffix(p) + vipSuffix(lb, vipIP)
+			data = append(data, controller.NameData{Kind: controller.KindPool, Port: suffix}, controller.NameData{Kind: controller.KindVIP, Port: suffix})
+		}
+	}
+	names := make(map[string]string, len(data))
+	for _, d := range data {
+		d.ClusterID, d.Namespace, d.Name = clusterID, lb.Namespace, lb.Name
+		name, err := namer.Name(d)
+		if err != nil {
+			return nil, err
+		}
+		names[d.Kind+"/"+d.Port] = name
+	}
+	return names, nil
+}
+
+// usesLegacyNames checks if the ExternalLoadBalancer was applied with the LegacyNamingTemplate,
+// the default before the namespace was added to it, comparing the applied monitor name
+func usesLegacyNames(lb *lbv1.ExternalLoadBalancer, b *controller.BackendController, clusterID string) bool {
+	if lb.Status.Monitor.Name == "" {
+		return false
+	}
+	legacy, err := b.Namer(controller.LegacyNamingTemplate)
+	if err != nil {
+		return false
+	}
+	name, err := legacy.Name(controller.NameData{Kind: controller.KindMonitor, ClusterID: clusterID, Namespace: lb.Namespace, Name: lb.Name})
+	return err == nil && name == lb.Status.Monitor.Name
+}
+
+// providerChanged returns true if the vendor or host of the provider the configuration was
+// applied to, recorded in the status, differs from the provider currently used
+func providerChanged(lb *lbv1.ExternalLoadBalancer, provider lbv1.Provider) bool {
+	return lb.Status.Provider.Vendor != "" &&
+		(lb.Status.Provider.Vendor != provider.Vendor || lb.Status.Provider.Host != provider.Host)
 }
 
 // computeLabels builds a label map with node role and additional labels
@@ -108,13 +304,17 @@ func computeLabels(lb lbv1.ExternalLoadBalancer) map[string]string {
 	return labels
 }
 
-// containsLabels checks if label map `as` contains labels from map `bs`
-func containsLabels(as, bs map[string]string) bool {
-	labels := make(map[string]string)
-	for k, v := range bs {
-		if _, ok := as[k]; ok {
-			labels[k] = v
-		}
+// nodeSelector builds the node label selector from the node role type, node labels and
+// label selector of the ExternalLoadBalancer. Nodes must match all of them.
+func nodeSelector(lb lbv1.ExternalLoadBalancer) (labels.Selector, error) {
+	selector := labels.SelectorFromSet(computeLabels(lb))
+	if lb.Spec.NodeSelector == nil {
+		return selector, nil
+	}
+	s, err := metav1.LabelSelectorAsSelector(lb.Spec.NodeSelector)
+	if err != nil {
+		return nil, err
 	}
-	return reflect.DeepEqual(bs, labels)
+	requirements, _ := s.Requirements()
+	return selector.Add(requirements...), nil
 }
@@ -27,9 +27,13 @@ package controllers
 import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
 
 	lbv1 "github.com/carlosedp/lbconfig-operator/api/v1"
+	backend "github.com/carlosedp/lbconfig-operator/internal/controller/backend/backend_controller"
 )
 
 var _ = Describe("ExternalLoadBalancer controller", func() {
@@ -42,16 +46,49 @@ var _ = Describe("ExternalLoadBalancer controller", func() {
 	)
 
 	Context("When using utility funtions", func() {
-		It("Should check contain labels", func() {
-			labels := map[string]string{"label1": "value1", "label2": "value2"}
-			containedLabels := map[string]string{"label1": "value1"}
-			notContainedLabels := map[string]string{"label3": "value3"}
+		It("Should match nodes with the node selector", func() {
+			nodeLabels := k8slabels.Set{"label1": "value1", "label2": "value2"}
+			loadBalancer := lbv1.ExternalLoadBalancer{
+				Spec: lbv1.ExternalLoadBalancerSpec{
+					NodeLabels: map[string]string{"label1": "value1"},
+				},
+			}
+
+			By("Checking if the node labels match")
+			selector, err := nodeSelector(loadBalancer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector.Matches(nodeLabels)).To(BeTrue())
 
-			By("Checking if valid labels are contained")
-			Expect(containsLabels(labels, containedLabels)).To(BeTrue())
+			By("Checking if a different label value does not match")
+			loadBalancer.Spec.NodeLabels = map[string]string{"label1": "value2"}
+			selector, err = nodeSelector(loadBalancer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector.Matches(nodeLabels)).To(BeFalse())
 
-			By("Checking if invalid labels are not contained")
-			Expect(containsLabels(labels, notContainedLabels)).To(BeFalse())
+			By("Checking the match expressions")
+			loadBalancer.Spec.NodeLabels = nil
+			loadBalancer.Spec.NodeSelector = &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "label1", Operator: metav1.LabelSelectorOpIn, Values: []string{"value1", "value3"}},
+					{Key: "label2", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"value3"}},
+					{Key: "label2", Operator: metav1.LabelSelectorOpExists},
+					{Key: "label3", Operator: metav1.LabelSelectorOpDoesNotExist},
+				},
+			}
+			selector, err = nodeSelector(loadBalancer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector.Matches(nodeLabels)).To(BeTrue())
+
+			By("Checking if the node selector is combined with the node labels")
+			loadBalancer.Spec.NodeLabels = map[string]string{"label3": "value3"}
+			selector, err = nodeSelector(loadBalancer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector.Matches(nodeLabels)).To(BeFalse())
+
+			By("Checking an invalid operator")
+			loadBalancer.Spec.NodeSelector.MatchExpressions[0].Operator = "Invalid"
+			_, err = nodeSelector(loadBalancer)
+			Expect(err).To(HaveOccurred())
 		})
 
 		It("Should compute labels from LoadBalancer instance", func() {
@@ -89,6 +126,140 @@ var _ = Describe("ExternalLoadBalancer controller", func() {
 			Expect(hasNodeChanged(n1, n3)).To(BeTrue())
 		})
 
+		It("Should check if nodes changed cordon or taints", func() {
+			n1 := createReadyNode("master-node-1", map[string]string{masterNodeLabel: ""}, "1.1.1.1")
+			n2 := n1.DeepCopy()
+			n2.Spec.Unschedulable = true
+			n3 := n1.DeepCopy()
+			n3.Spec.Taints = []corev1.Taint{{Key: "maintenance", Effect: corev1.TaintEffectNoSchedule}}
+
+			Expect(hasNodeChanged(n1, n1.DeepCopy())).To(BeFalse())
+			Expect(hasNodeChanged(n1, n2)).To(BeTrue())
+			Expect(hasNodeChanged(n1, n3)).To(BeTrue())
+		})
+
+		It("Should select the node address by the address policy", func() {
+			node := createReadyNode("master-node-1", map[string]string{masterNodeLabel: ""}, "1.1.1.1")
+			node.Status.Addresses = append(node.Status.Addresses,
+				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.1"},
+				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "fd00::1"},
+				corev1.NodeAddress{Type: corev1.NodeHostName, Address: "master-node-1.example.com"},
+			)
+
+			By("Preferring the ExternalIP by default")
+			policy, err := newNodeAddressPolicy(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getNodeIP(node, false, policy)).To(Equal("1.1.1.1"))
+			Expect(getNodeIP(node, true, policy)).To(Equal("fd00::1"))
+
+			By("Following the address type priority")
+			policy, err = newNodeAddressPolicy(&lbv1.NodeAddress{Types: []string{lbv1.NodeAddressInternalIP, lbv1.NodeAddressExternalIP}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getNodeIP(node, false, policy)).To(Equal("10.0.0.1"))
+			policy, err = newNodeAddressPolicy(&lbv1.NodeAddress{Types: []string{lbv1.NodeAddressHostname}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getNodeIP(node, false, policy)).To(Equal("master-node-1.example.com"))
+
+			By("Filtering the addresses by CIDR")
+			policy, err = newNodeAddressPolicy(&lbv1.NodeAddress{CIDRs: []string{"10.0.0.0/8"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getNodeIP(node, false, policy)).To(Equal("10.0.0.1"))
+			Expect(getNodeIP(node, true, policy)).To(BeEmpty())
+			_, err = newNodeAddressPolicy(&lbv1.NodeAddress{CIDRs: []string{"10.0.0.0"}})
+			Expect(err).To(HaveOccurred())
+
+			By("Overriding the address with the node annotation")
+			node.Annotations = map[string]string{nodeAddressAnnotation: "192.168.0.1, fd01::1"}
+			Expect(getNodeIP(node, false, policy)).To(Equal("192.168.0.1"))
+			Expect(getNodeIP(node, true, policy)).To(Equal("fd01::1"))
+
+			By("Checking if a node address annotation change is detected")
+			Expect(hasNodeChanged(node, createReadyNode("master-node-1", map[string]string{masterNodeLabel: ""}, "1.1.1.1"))).To(BeTrue())
+		})
+
+		It("Should exclude cordoned, labeled and tainted nodes", func() {
+			taints := []lbv1.NodeTaint{{Key: "maintenance", Effect: "NoSchedule"}, {Key: "lb", Value: "off"}}
+			node := createReadyNode("master-node-1", map[string]string{masterNodeLabel: ""}, "1.1.1.1")
+			Expect(nodeExclusionReason(node, taints)).To(BeEmpty())
+
+			cordoned := node.DeepCopy()
+			cordoned.Spec.Unschedulable = true
+			Expect(nodeExclusionReason(cordoned, nil)).To(ContainSubstring("cordoned"))
+
+			labeled := node.DeepCopy()
+			labeled.Labels[corev1.LabelNodeExcludeBalancers] = ""
+			Expect(nodeExclusionReason(labeled, nil)).To(ContainSubstring(corev1.LabelNodeExcludeBalancers))
+
+			tainted := node.DeepCopy()
+			tainted.Spec.Taints = []corev1.Taint{{Key: "maintenance", Effect: corev1.TaintEffectNoExecute}}
+			Expect(nodeExclusionReason(tainted, taints)).To(BeEmpty())
+			tainted.Spec.Taints = []corev1.Taint{{Key: "maintenance", Effect: corev1.TaintEffectNoSchedule}}
+			Expect(nodeExclusionReason(tainted, taints)).To(ContainSubstring("maintenance:NoSchedule"))
+			tainted.Spec.Taints = []corev1.Taint{{Key: "lb", Value: "on", Effect: corev1.TaintEffectNoSchedule}}
+			Expect(nodeExclusionReason(tainted, taints)).To(BeEmpty())
+			tainted.Spec.Taints = []corev1.Taint{{Key: "lb", Value: "off", Effect: corev1.TaintEffectPreferNoSchedule}}
+			Expect(nodeExclusionReason(tainted, taints)).To(ContainSubstring("lb=off"))
+		})
+
+		It("Should set status conditions after a successful reconcile", func() {
+			loadBalancer := lbv1.ExternalLoadBalancer{}
+			setSucceededConditions(&loadBalancer, nil)
+			Expect(meta.IsStatusConditionTrue(loadBalancer.Status.Conditions, lbv1.ConditionSynced)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(loadBalancer.Status.Conditions, lbv1.ConditionReady)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(loadBalancer.Status.Conditions, lbv1.ConditionDegraded)).To(BeTrue())
+
+			loadBalancer.Status.NumNodes = 2
+			setSucceededConditions(&loadBalancer, nil)
+			Expect(meta.IsStatusConditionTrue(loadBalancer.Status.Conditions, lbv1.ConditionReady)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(loadBalancer.Status.Conditions, lbv1.ConditionDegraded)).To(BeTrue())
+
+			setSucceededConditions(&loadBalancer, []string{"refused to remove 2 of 2 members from pool Pool-test-443"})
+			Expect(meta.IsStatusConditionTrue(loadBalancer.Status.Conditions, lbv1.ConditionReady)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(loadBalancer.Status.Conditions, lbv1.ConditionDegraded)).To(BeTrue())
+			Expect(meta.FindStatusCondition(loadBalancer.Status.Conditions, lbv1.ConditionDegraded).Reason).To(Equal(reasonRemovalRefused))
+		})
+
+		It("Should check if the reconciliation is paused", func() {
+			loadBalancer := &lbv1.ExternalLoadBalancer{}
+			Expect(isPaused(loadBalancer)).To(BeFalse())
+			loadBalancer.Annotations = map[string]string{pausedAnnotation: "false"}
+			Expect(isPaused(loadBalancer)).To(BeFalse())
+			loadBalancer.Annotations[pausedAnnotation] = "true"
+			Expect(isPaused(loadBalancer)).To(BeTrue())
+		})
+
+		It("Should check if the provider vendor or host changed", func() {
+			loadBalancer := &lbv1.ExternalLoadBalancer{}
+			loadBalancer.Spec.Provider = lbv1.Provider{Vendor: "F5_BigIP", Host: "https://10.0.0.1", Creds: "creds"}
+			Expect(providerChanged(loadBalancer, loadBalancer.Spec.Provider)).To(BeFalse())
+			loadBalancer.Status.Provider = loadBalancer.Spec.Provider
+			loadBalancer.Spec.Provider.Creds = "other-creds"
+			Expect(providerChanged(loadBalancer, loadBalancer.Spec.Provider)).To(BeFalse())
+			loadBalancer.Spec.Provider.Host = "https://10.0.0.2"
+			Expect(providerChanged(loadBalancer, loadBalancer.Spec.Provider)).To(BeTrue())
+			loadBalancer.Spec.Provider.Host = loadBalancer.Status.Provider.Host
+			loadBalancer.Spec.Provider.Vendor = "HAProxy"
+			Expect(providerChanged(loadBalancer, loadBalancer.Spec.Provider)).To(BeTrue())
+		})
+
+		It("Should keep the legacy names of the instances applied with them", func() {
+			loadBalancer := &lbv1.ExternalLoadBalancer{ObjectMeta: metav1.ObjectMeta{Name: "lb", Namespace: "default"}}
+			loadBalancer.Spec.Ports = []int{443}
+			b := &backend.BackendController{}
+			Expect(usesLegacyNames(loadBalancer, b, "cluster")).To(BeFalse())
+			names, err := objectNames(loadBalancer, b, backend.DefaultNamingTemplate, "cluster")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(HaveKeyWithValue("VIP/443", "VIP-default-lb-443"))
+
+			loadBalancer.Status.Monitor.Name = "Monitor-lb"
+			Expect(usesLegacyNames(loadBalancer, b, "cluster")).To(BeTrue())
+			names, err = objectNames(loadBalancer, b, backend.LegacyNamingTemplate, "cluster")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(HaveKeyWithValue("Pool/443", "Pool-lb-443"))
+			loadBalancer.Status.Monitor.Name = "Monitor-default-lb"
+			Expect(usesLegacyNames(loadBalancer, b, "cluster")).To(BeFalse())
+		})
+
 		It("Should check if nodes changed IP addresses", func() {
 			n1 := createReadyNode("master-node-1", map[string]string{masterNodeLabel: ""}, "1.1.1.1")
 			n2 := createReadyNode("master-node-1", map[string]string{masterNodeLabel: ""}, "1.1.1.1")
@@ -0,0 +1,493 @@
+/*
+MIT License
+
+Copyright (c) 2022 Carlos Eduardo de Paula
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	lbv1 "github.com/carlosedp/lbconfig-operator/api/v1"
+	backend "github.com/carlosedp/lbconfig-operator/internal/controller/backend/backend_controller"
+)
+
+const (
+	// f5Vendor is the only vendor supporting the F5 specific fields
+	f5Vendor = "F5_BigIP"
+	// citrixADCVendor is the only vendor supporting the Citrix ADC specific fields
+	citrixADCVendor = "Citrix_ADC"
+	// haproxyVendor only supports TCP ports
+	haproxyVendor = "HAProxy"
+	// wildcardVIP binds the HAProxy frontends on all the addresses
+	wildcardVIP = "*"
+)
+
+// log is for logging in this package.
+var externalloadbalancerlog = logf.Log.WithName("externalloadbalancer-resource")
+
+// SetupExternalLoadBalancerWebhookWithManager registers the webhook for ExternalLoadBalancer in the manager.
+func SetupExternalLoadBalancerWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &lbv1.ExternalLoadBalancer{}).
+		WithValidator(&ExternalLoadBalancerCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-lb-lbconfig-carlosedp-com-v1-externalloadbalancer,mutating=false,failurePolicy=fail,sideEffects=None,groups=lb.lbconfig.carlosedp.com,resources=externalloadbalancers,verbs=create;update,versions=v1,name=vexternalloadbalancer-v1.kb.io,admissionReviewVersions=v1
+
+// ExternalLoadBalancerCustomValidator validates the ExternalLoadBalancer resource when it is created or updated.
+// Checks that depend on other objects (credentials Secret, VIPs used by other instances) are done
+// against the cluster using the Client.
+type ExternalLoadBalancerCustomValidator struct {
+	Client client.Client
+}
+
+// ValidateCreate implements admission.Validator so a webhook will be registered for the type ExternalLoadBalancer.
+func (v *ExternalLoadBalancerCustomValidator) ValidateCreate(ctx context.Context, lb *lbv1.ExternalLoadBalancer) (admission.Warnings, error) {
+	externalloadbalancerlog.Info("Validation for ExternalLoadBalancer upon creation", "name", lb.GetName())
+	return nil, v.validateExternalLoadBalancer(ctx, lb)
+}
+
+// ValidateUpdate implements admission.Validator so a webhook will be registered for the type ExternalLoadBalancer.
+func (v *ExternalLoadBalancerCustomValidator) ValidateUpdate(ctx context.Context, oldLB, newLB *lbv1.ExternalLoadBalancer) (admission.Warnings, error) {
+	externalloadbalancerlog.Info("Validation for ExternalLoadBalancer upon update", "name", newLB.GetName())
+
+	// Metadata only updates (like adding or removing the finalizer) must not be blocked,
+	// otherwise an instance whose Secret was removed could never be deleted.
+	if !newLB.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(oldLB.Spec, newLB.Spec) {
+		return nil, nil
+	}
+	return nil, v.validateExternalLoadBalancer(ctx, newLB)
+}
+
+// ValidateDelete implements admission.Validator so a webhook will be registered for the type ExternalLoadBalancer.
+func (v *ExternalLoadBalancerCustomValidator) ValidateDelete(_ context.Context, _ *lbv1.ExternalLoadBalancer) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateExternalLoadBalancer runs all validations returning an Invalid error with every field failing
+func (v *ExternalLoadBalancerCustomValidator) validateExternalLoadBalancer(ctx context.Context, lb *lbv1.ExternalLoadBalancer) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	providerPath := specPath.Child("provider")
+
+	if lb.Spec.Type == "" && len(lb.Spec.NodeLabels) == 0 && lb.Spec.NodeSelector == nil {
+		allErrs = append(allErrs, field.Required(specPath.Child("type"), "either type, nodelabels or nodeselector must be set"))
+	}
+	allErrs = append(allErrs, metav1validation.ValidateLabelSelector(lb.Spec.NodeSelector, metav1validation.LabelSelectorValidationOptions{}, specPath.Child("nodeselector"))...)
+	if lb.Spec.NodeAddress != nil {
+		for i, c := range lb.Spec.NodeAddress.CIDRs {
+			if _, _, err := net.ParseCIDR(c); err != nil {
+				allErrs = append(allErrs, field.Invalid(specPath.Child("nodeaddress", "cidrs").Index(i), c, "must be a CIDR like 10.0.0.0/24"))
+			}
+		}
+	}
+
+	if lb.Spec.ResyncPeriod != nil && lb.Spec.ResyncPeriod.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("resyncperiod"), lb.Spec.ResyncPeriod.Duration.String(), "must not be negative"))
+	}
+
+	if lb.Spec.NamingTemplate != "" {
+		if _, err := backend.NewNamer(lb.Spec.NamingTemplate, backend.NamingRules{}); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("namingtemplate"), lb.Spec.NamingTemplate, err.Error()))
+		}
+	}
+
+	provider, fieldErrs, err := v.resolveProvider(ctx, lb, specPath)
+	if err != nil {
+		return err
+	}
+	allErrs = append(allErrs, fieldErrs...)
+	allErrs = append(allErrs, validatePorts(lb, provider.Vendor, specPath)...)
+
+	allErrs = append(allErrs, validateVendorOptions(&lb.Spec.Provider, provider.Vendor, providerPath)...)
+	allErrs = append(allErrs, validateMonitor(&lb.Spec.Monitor, provider.Vendor, specPath.Child("monitor"))...)
+	allErrs = append(allErrs, validateNodeAddress(lb.Spec.NodeAddress, provider.Vendor, specPath.Child("nodeaddress"))...)
+
+	if lb.Spec.Vip != wildcardVIP || provider.Vendor != haproxyVendor {
+		if err := validateIPAddress(lb.Spec.Vip, provider.Vendor == f5Vendor); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("vip"), lb.Spec.Vip, err.Error()))
+		}
+	}
+
+	if lb.Spec.SecondaryVip != "" {
+		if err := validateIPAddress(lb.Spec.SecondaryVip, provider.Vendor == f5Vendor); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("secondaryvip"), lb.Spec.SecondaryVip, err.Error()))
+		} else if backend.IsIPv6(lb.Spec.SecondaryVip) == backend.IsIPv6(lb.Spec.Vip) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("secondaryvip"), lb.Spec.SecondaryVip, "must be from a different IP family than vip"))
+		}
+	}
+
+	fieldErrs, err = v.validateVIP(ctx, lb, provider, specPath)
+	if err != nil {
+		return err
+	}
+	allErrs = append(allErrs, fieldErrs...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(lbv1.GroupVersion.WithKind("ExternalLoadBalancer").GroupKind(), lb.Name, allErrs)
+}
+
+// validateProviderHost checks the provider host is an http or https URL
+func validateProviderHost(host string) error {
+	u, err := url.Parse(host)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be a URL in the format http(s)://<host>")
+	}
+	return nil
+}
+
+// resolveProvider returns the provider used by the ExternalLoadBalancer, built from the referenced
+// LoadBalancerProvider when providerref is set, and checks its credentials Secret exists
+func (v *ExternalLoadBalancerCustomValidator) resolveProvider(ctx context.Context, lb *lbv1.ExternalLoadBalancer, specPath *field.Path) (lbv1.Provider, field.ErrorList, error) {
+	var allErrs field.ErrorList
+	providerPath := specPath.Child("provider")
+	refPath := specPath.Child("providerref")
+
+	if lb.Spec.ProviderRef == "" {
+		providers := backend.ListProviders()
+		slices.Sort(providers)
+		if !slices.Contains(providers, strings.ToLower(lb.Spec.Provider.Vendor)) {
+			allErrs = append(allErrs, field.NotSupported(providerPath.Child("vendor"), lb.Spec.Provider.Vendor, providers))
+		}
+		if err := validateProviderHost(lb.Spec.Provider.Host); err != nil {
+			allErrs = append(allErrs, field.Invalid(providerPath.Child("host"), lb.Spec.Provider.Host, err.Error()))
+		}
+		// Only the LoadBalancerProvider secrets can be in another namespace
+		if strings.Contains(lb.Spec.Provider.Creds, "/") {
+			return lb.Spec.Provider, append(allErrs, field.Invalid(providerPath.Child("creds"), lb.Spec.Provider.Creds, "must be a secret name in the ExternalLoadBalancer namespace")), nil
+		}
+		fieldErr, err := v.validateCredentials(ctx, lb.Spec.Provider, lb.Namespace, providerPath.Child("creds"))
+		if err != nil {
+			return lbv1.Provider{}, nil, err
+		}
+		if fieldErr != nil {
+			allErrs = append(allErrs, fieldErr)
+		}
+		return lb.Spec.Provider, allErrs, nil
+	}
+
+	connectionFields := []struct {
+		name string
+		set  bool
+	}{
+		{"vendor", lb.Spec.Provider.Vendor != ""},
+		{"host", lb.Spec.Provider.Host != ""},
+		{"port", lb.Spec.Provider.Port != 0},
+		{"creds", lb.Spec.Provider.Creds != ""},
+	}
+	for _, f := range connectionFields {
+		if f.set {
+			allErrs = append(allErrs, field.Forbidden(providerPath.Child(f.name), "set in the LoadBalancerProvider referenced by providerref"))
+		}
+	}
+
+	lbp := &lbv1.LoadBalancerProvider{}
+	err := v.Client.Get(ctx, types.NamespacedName{Name: lb.Spec.ProviderRef}, lbp)
+	if apierrors.IsNotFound(err) {
+		return lbv1.Provider{}, append(allErrs, field.NotFound(refPath, lb.Spec.ProviderRef)), nil
+	}
+	if err != nil {
+		return lbv1.Provider{}, nil, fmt.Errorf("error getting LoadBalancerProvider %s: %w", lb.Spec.ProviderRef, err)
+	}
+	ns := &corev1.Namespace{}
+	if err := v.Client.Get(ctx, types.NamespacedName{Name: lb.Namespace}, ns); err != nil {
+		return lbv1.Provider{}, nil, fmt.Errorf("error getting namespace %s: %w", lb.Namespace, err)
+	}
+	allowed, err := lbp.AllowsNamespace(ns.Labels)
+	if err != nil || !allowed {
+		return lbv1.Provider{}, append(allErrs, field.Forbidden(refPath, fmt.Sprintf("namespace %s is not allowed to use LoadBalancerProvider %s", lb.Namespace, lbp.Name))), nil
+	}
+
+	provider := lbp.Provider(lb.Spec.Provider)
+	fieldErr, err := v.validateCredentials(ctx, provider, lb.Namespace, refPath)
+	if err != nil {
+		return lbv1.Provider{}, nil, err
+	}
+	if fieldErr != nil {
+		allErrs = append(allErrs, fieldErr)
+	}
+	return provider, allErrs, nil
+}
+
+// validateCredentials checks the provider credentials Secret exists, in the ExternalLoadBalancer
+// namespace unless the provider comes from a LoadBalancerProvider
+func (v *ExternalLoadBalancerCustomValidator) validateCredentials(ctx context.Context, provider lbv1.Provider, namespace string, path *field.Path) (*field.Error, error) {
+	secret := &corev1.Secret{}
+	err := v.Client.Get(ctx, provider.CredsSecret(namespace), secret)
+	if apierrors.IsNotFound(err) {
+		return field.NotFound(path, provider.Creds), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting credentials secret %s: %w", provider.Creds, err)
+	}
+	return nil, nil
+}
+
+// validatePorts checks at least one port is set and the ports are unique per protocol
+func validatePorts(lb *lbv1.ExternalLoadBalancer, vendor string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(lb.Spec.Ports) == 0 && len(lb.Spec.ServicePorts) == 0 {
+		return append(allErrs, field.Required(path.Child("serviceports"), "either ports or serviceports must be set"))
+	}
+	if len(lb.Spec.Ports) > 0 && len(lb.Spec.ServicePorts) > 0 {
+		return append(allErrs, field.Forbidden(path.Child("ports"), "ports and serviceports can't be set together, set all the ports in serviceports"))
+	}
+
+	ports := make(map[string]bool)
+	for i, p := range lb.Spec.Ports {
+		key := fmt.Sprintf("%d/%s", p, lbv1.ProtocolTCP)
+		if ports[key] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("ports").Index(i), p))
+		}
+		ports[key] = true
+	}
+	names := make(map[string]bool)
+	for i, p := range lb.Spec.ServicePorts {
+		portPath := path.Child("serviceports").Index(i)
+		protocol := p.Protocol
+		if protocol == "" {
+			protocol = lbv1.ProtocolTCP
+		}
+		key := fmt.Sprintf("%d/%s", p.Port, protocol)
+		if ports[key] {
+			allErrs = append(allErrs, field.Duplicate(portPath.Child("port"), p.Port))
+		}
+		ports[key] = true
+		if p.Name != "" {
+			if names[p.Name] {
+				allErrs = append(allErrs, field.Duplicate(portPath.Child("name"), p.Name))
+			}
+			names[p.Name] = true
+		}
+		if protocol == lbv1.ProtocolUDP && vendor == haproxyVendor {
+			allErrs = append(allErrs, field.NotSupported(portPath.Child("protocol"), protocol, []string{lbv1.ProtocolTCP}))
+		}
+	}
+	return allErrs
+}
+
+// validateVendorOptions checks the vendor specific options are only set for their vendor.
+// The vendor is empty when the provider couldn't be resolved.
+func validateVendorOptions(p *lbv1.Provider, vendor string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if p.Partition != "" && p.F5 != nil && p.F5.Partition != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("partition"), "partition is deprecated and can't be set together with f5.partition"))
+	}
+	if vendor == "" {
+		return allErrs
+	}
+	options := []struct {
+		name   string
+		set    bool
+		vendor string
+	}{
+		{"partition", p.Partition != "", f5Vendor},
+		{"f5", p.F5 != nil, f5Vendor},
+		{"citrixADC", p.CitrixADC != nil, citrixADCVendor},
+		{"haproxy", p.HAProxy != nil, haproxyVendor},
+	}
+	for _, o := range options {
+		if o.set && vendor != o.vendor {
+			allErrs = append(allErrs, field.Forbidden(path.Child(o.name), o.name+" is only supported by the "+o.vendor+" vendor"))
+		}
+	}
+	return allErrs
+}
+
+// validateNodeAddress checks the Hostname address type is only used on HAProxy, which resolves
+// the server names. F5 and Citrix ADC require the pool member IP addresses.
+func validateNodeAddress(nodeAddress *lbv1.NodeAddress, vendor string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if nodeAddress == nil || (vendor != f5Vendor && vendor != citrixADCVendor) {
+		return nil
+	}
+	for i, t := range nodeAddress.Types {
+		if t == lbv1.NodeAddressHostname {
+			allErrs = append(allErrs, field.Forbidden(path.Child("types").Index(i), t+" is not supported by the "+vendor+" vendor"))
+		}
+	}
+	return allErrs
+}
+
+// validateMonitor checks the monitor timers, that the HTTP fields are only set on HTTP monitors
+// and that the check parameters are supported by the vendor.
+// The timeout is the time to mark the member down after the checks fail on F5, so it must be greater
+// than the interval, and the check response timeout on Citrix ADC, so it must be less. HAProxy ignores it.
+func validateMonitor(m *lbv1.Monitor, vendor string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	monitor := m.WithDefaults()
+	if vendor == f5Vendor && monitor.Timeout <= monitor.Interval {
+		allErrs = append(allErrs, field.Invalid(path.Child("timeout"), monitor.Timeout, "must be greater than the interval"))
+	}
+	if vendor == citrixADCVendor {
+		timeout := m.Timeout
+		if timeout == 0 {
+			timeout = lbv1.DefaultCitrixADCMonitorTimeout
+		}
+		if timeout >= monitor.Interval {
+			allErrs = append(allErrs, field.Invalid(path.Child("timeout"), timeout, "must be less than the interval on "+citrixADCVendor))
+		}
+	}
+
+	isHTTP := m.MonitorType == lbv1.MonitorTypeHTTP || m.MonitorType == lbv1.MonitorTypeHTTPS
+	isTCP := m.MonitorType == lbv1.MonitorTypeTCP
+	if isHTTP && m.Path == "" {
+		allErrs = append(allErrs, field.Required(path.Child("path"), "required for http and https monitors"))
+	}
+	monitorFields := []struct {
+		name      string
+		set       bool
+		supported bool
+		types     string
+	}{
+		{"expectedstatus", m.ExpectedStatus != 0, isHTTP, "http and https"},
+		{"receive", m.Receive != "", isHTTP || isTCP, "http, https and tcp"},
+		{"send", m.Send != "", isTCP, "tcp"},
+		{"host", m.Host != "", isHTTP, "http and https"},
+		{"sni", m.SNI != "", m.MonitorType == lbv1.MonitorTypeHTTPS, "https"},
+	}
+	for _, f := range monitorFields {
+		if f.set && !f.supported {
+			allErrs = append(allErrs, field.Forbidden(path.Child(f.name), "only supported by "+f.types+" monitors"))
+		}
+	}
+
+	// The check parameters the vendor can't configure are rejected instead of being ignored
+	vendorFields := []struct {
+		name        string
+		set         bool
+		unsupported []string
+	}{
+		{"rise", m.Rise != 0, []string{f5Vendor}},
+		{"fall", m.Fall != 0, []string{f5Vendor}},
+		{"sni", m.SNI != "", []string{f5Vendor, citrixADCVendor}},
+	}
+	for _, f := range vendorFields {
+		if f.set && slices.Contains(f.unsupported, vendor) {
+			allErrs = append(allErrs, field.Forbidden(path.Child(f.name), f.name+" is not supported by the "+vendor+" vendor"))
+		}
+	}
+	return allErrs
+}
+
+// validateIPAddress checks the address is an IPv4 or IPv6 address.
+// F5 route domains in the "%<id>" notation are accepted when allowRouteDomain is set.
+func validateIPAddress(address string, allowRouteDomain bool) error {
+	if i := strings.Index(address, "%"); i >= 0 && allowRouteDomain {
+		if _, err := strconv.Atoi(address[i+1:]); err != nil {
+			return fmt.Errorf("route domain must be numeric")
+		}
+		address = address[:i]
+	}
+	if net.ParseIP(address) == nil {
+		return fmt.Errorf("must be a valid IPv4 or IPv6 address")
+	}
+	return nil
+}
+
+// validateVIP checks no other ExternalLoadBalancer uses the same VIPs, service ports and protocols
+// on the same provider host and port
+func (v *ExternalLoadBalancerCustomValidator) validateVIP(ctx context.Context, lb *lbv1.ExternalLoadBalancer, provider lbv1.Provider, path *field.Path) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+	lbs := &lbv1.ExternalLoadBalancerList{}
+	if err := v.Client.List(ctx, lbs); err != nil {
+		return nil, fmt.Errorf("error listing ExternalLoadBalancers: %w", err)
+	}
+	lbps := &lbv1.LoadBalancerProviderList{}
+	if err := v.Client.List(ctx, lbps); err != nil {
+		return nil, fmt.Errorf("error listing LoadBalancerProviders: %w", err)
+	}
+	providerRefs := make(map[string]*lbv1.LoadBalancerProvider, len(lbps.Items))
+	for i := range lbps.Items {
+		providerRefs[lbps.Items[i].Name] = &lbps.Items[i]
+	}
+	vips := map[string]string{"vip": lb.Spec.Vip, "secondaryvip": lb.Spec.SecondaryVip}
+	for _, child := range []string{"vip", "secondaryvip"} {
+		vip := vips[child]
+		if vip == "" {
+			continue
+		}
+		for _, other := range lbs.Items {
+			if other.Namespace == lb.Namespace && other.Name == lb.Name {
+				continue
+			}
+			otherProvider := other.Spec.Provider
+			if other.Spec.ProviderRef != "" {
+				lbp, ok := providerRefs[other.Spec.ProviderRef]
+				if !ok {
+					continue
+				}
+				otherProvider = lbp.Provider(other.Spec.Provider)
+			}
+			if !sameVIP(other.Spec.Vip, vip) && !sameVIP(other.Spec.SecondaryVip, vip) ||
+				!strings.EqualFold(otherProvider.Host, provider.Host) ||
+				otherProvider.Port != provider.Port {
+				continue
+			}
+			// Instances can share a VIP listening on different ports
+			if shared := sharedPorts(&lb.Spec, &other.Spec); len(shared) > 0 {
+				allErrs = append(allErrs, field.Invalid(path.Child(child), vip, fmt.Sprintf("VIP ports %s are already used by ExternalLoadBalancer %s/%s on provider %s:%d",
+					strings.Join(shared, ", "), other.Namespace, other.Name, provider.Host, provider.Port)))
+				break
+			}
+		}
+	}
+	return allErrs, nil
+}
+
+// sameVIP checks if the addresses are the same VIP. The HAProxy wildcard VIP binds on
+// all the addresses so it matches any other VIP.
+func sameVIP(a, b string) bool {
+	return a != "" && b != "" && (a == b || a == wildcardVIP || b == wildcardVIP)
+}
+
+// sharedPorts returns the service ports with the same port and protocol in both instances
+func sharedPorts(a, b *lbv1.ExternalLoadBalancerSpec) []string {
+	var shared []string
+	otherPorts := b.GetServicePorts()
+	for _, p := range a.GetServicePorts() {
+		if slices.ContainsFunc(otherPorts, func(o lbv1.ServicePort) bool { return o.Port == p.Port && o.Protocol == p.Protocol }) {
+			shared = append(shared, fmt.Sprintf("%d/%s", p.Port, p.Protocol))
+		}
+	}
+	return shared
+}
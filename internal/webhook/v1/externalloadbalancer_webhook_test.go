@@ -0,0 +1,577 @@
+/*
+MIT License
+
+Copyright (c) 2022 Carlos Eduardo de Paula
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	lbv1 "github.com/carlosedp/lbconfig-operator/api/v1"
+	_ "github.com/carlosedp/lbconfig-operator/internal/controller/backend/backend_loader"
+)
+
+func TestWebhook(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Webhook Suite")
+}
+
+const testNamespace = "default"
+
+func newLoadBalancer(name string) *lbv1.ExternalLoadBalancer {
+	return &lbv1.ExternalLoadBalancer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		Spec: lbv1.ExternalLoadBalancerSpec{
+			Vip:   "10.0.0.1",
+			Type:  "master",
+			Ports: []int{6443},
+			Monitor: lbv1.Monitor{
+				Path:        "/health",
+				Port:        1936,
+				MonitorType: "http",
+			},
+			Provider: lbv1.Provider{
+				Vendor: "Dummy",
+				Host:   "https://1.2.3.4",
+				Port:   443,
+				Creds:  "creds-secret",
+			},
+		},
+	}
+}
+
+var _ = Describe("ExternalLoadBalancer Webhook", func() {
+	var (
+		ctx       = context.TODO()
+		validator *ExternalLoadBalancerCustomValidator
+		lb        *lbv1.ExternalLoadBalancer
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(lbv1.AddToScheme(scheme)).To(Succeed())
+
+		existing := newLoadBalancer("existing-lb")
+		existing.Spec.Vip = "10.0.0.2"
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "creds-secret", Namespace: testNamespace},
+			Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("admin")},
+		}
+		validator = &ExternalLoadBalancerCustomValidator{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing, secret).Build(),
+		}
+		lb = newLoadBalancer("test-lb")
+	})
+
+	expectInvalid := func(err error, field string) {
+		Expect(err).To(HaveOccurred())
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring(field))
+	}
+
+	Context("When creating an ExternalLoadBalancer", func() {
+		It("Should admit a valid instance", func() {
+			_, err := validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should admit an instance using nodelabels instead of type", func() {
+			lb.Spec.Type = ""
+			lb.Spec.NodeLabels = map[string]string{"node-role.kubernetes.io/infra": ""}
+			_, err := validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should admit an instance using nodeselector instead of type", func() {
+			lb.Spec.Type = ""
+			lb.Spec.NodeSelector = &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "node-role.kubernetes.io/infra", Operator: metav1.LabelSelectorOpExists},
+				},
+			}
+			_, err := validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a nodeselector with an invalid operator", func() {
+			lb.Spec.NodeSelector = &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "node-role.kubernetes.io/infra", Operator: "Invalid"},
+				},
+			}
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.nodeselector.matchExpressions[0].operator")
+		})
+
+		It("Should deny an invalid nodeaddress CIDR", func() {
+			lb.Spec.NodeAddress = &lbv1.NodeAddress{CIDRs: []string{"10.0.0.0/8", "10.0.0.1"}}
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.nodeaddress.cidrs[1]")
+		})
+
+		It("Should deny the Hostname node address type on F5 and Citrix ADC", func() {
+			lb.Spec.NodeAddress = &lbv1.NodeAddress{Types: []string{lbv1.NodeAddressInternalIP, lbv1.NodeAddressHostname}}
+			for _, vendor := range []string{"F5_BigIP", "Citrix_ADC"} {
+				lb.Spec.Provider.Vendor = vendor
+				_, err := validator.ValidateCreate(ctx, lb)
+				expectInvalid(err, "spec.nodeaddress.types[1]")
+			}
+
+			lb.Spec.Provider.Vendor = "HAProxy"
+			_, err := validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a negative resyncperiod", func() {
+			lb.Spec.ResyncPeriod = &metav1.Duration{Duration: -time.Minute}
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.resyncperiod")
+		})
+
+		It("Should admit a naming template with the cluster ID", func() {
+			lb.Spec.NamingTemplate = "{{.ClusterID}}-{{.Kind}}-{{.Namespace}}-{{.Name}}-{{.Port}}"
+			_, err := validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a naming template without unique names", func() {
+			lb.Spec.NamingTemplate = "{{.Name}}"
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.namingtemplate")
+
+			lb.Spec.NamingTemplate = "{{.Kind}-{{.Name}}"
+			_, err = validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.namingtemplate")
+		})
+
+		It("Should deny an instance without type, nodelabels or nodeselector", func() {
+			lb.Spec.Type = ""
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.type")
+		})
+
+		It("Should deny an unknown vendor", func() {
+			lb.Spec.Provider.Vendor = "unknown"
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.provider.vendor")
+		})
+
+		It("Should deny a provider host that is not an URL", func() {
+			lb.Spec.Provider.Host = "1.2.3.4"
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.provider.host")
+		})
+
+		It("Should deny a missing credentials secret", func() {
+			lb.Spec.Provider.Creds = "missing-secret"
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.provider.creds")
+		})
+
+		It("Should deny F5 only fields on other vendors", func() {
+			lb.Spec.Provider.Partition = "Common"
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.provider.partition")
+
+			lb.Spec.Provider.Vendor = "F5_BigIP"
+			_, err = validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny the vendor options on other vendors", func() {
+			lb.Spec.Provider.F5 = &lbv1.F5Options{Partition: "Common"}
+			lb.Spec.Provider.CitrixADC = &lbv1.CitrixADCOptions{SaveConfig: ptr.To(false)}
+			lb.Spec.Provider.HAProxy = &lbv1.HAProxyOptions{BasePath: "/v2"}
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.provider.f5")
+			expectInvalid(err, "spec.provider.citrixADC")
+			expectInvalid(err, "spec.provider.haproxy")
+
+			lb.Spec.Provider.Vendor = "F5_BigIP"
+			lb.Spec.Provider.CitrixADC = nil
+			lb.Spec.Provider.HAProxy = nil
+			_, err = validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny the deprecated partition together with the f5 options partition", func() {
+			lb.Spec.Provider.Vendor = "F5_BigIP"
+			lb.Spec.Provider.Partition = "Common"
+			lb.Spec.Provider.F5 = &lbv1.F5Options{Partition: "Tenant1"}
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.provider.partition")
+		})
+
+		It("Should deny a VIP already used on the same provider", func() {
+			lb.Spec.Vip = "10.0.0.2"
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.vip")
+			Expect(err.Error()).To(ContainSubstring("existing-lb"))
+		})
+
+		It("Should admit a VIP used on the same provider with other ports or protocols", func() {
+			lb.Spec.Vip = "10.0.0.2"
+			lb.Spec.Ports = []int{443}
+			_, err := validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+
+			lb.Spec.Ports = nil
+			lb.Spec.ServicePorts = []lbv1.ServicePort{{Port: 6443, Protocol: lbv1.ProtocolUDP}}
+			_, err = validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+
+			lb.Spec.ServicePorts = append(lb.Spec.ServicePorts, lbv1.ServicePort{Port: 6443})
+			_, err = validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.vip")
+			Expect(err.Error()).To(ContainSubstring("6443/TCP"))
+		})
+
+		It("Should admit IPv6 and dual-stack VIPs", func() {
+			lb.Spec.Vip = "2001:db8::1"
+			_, err := validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+
+			lb.Spec.SecondaryVip = "10.0.0.5"
+			_, err = validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny an invalid VIP address", func() {
+			lb.Spec.Vip = "10.0.0.300"
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.vip")
+		})
+
+		It("Should accept the wildcard VIP only on HAProxy", func() {
+			lb.Spec.Vip = "*"
+			lb.Spec.Ports = []int{8443}
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.vip")
+
+			lb.Spec.Provider.Vendor = "HAProxy"
+			_, err = validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Denying the wildcard on the ports used by another VIP")
+			lb.Spec.Ports = []int{6443}
+			_, err = validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.vip")
+		})
+
+		It("Should accept route domains only on F5", func() {
+			lb.Spec.Vip = "10.0.0.1%2"
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.vip")
+
+			lb.Spec.Provider.Vendor = "F5_BigIP"
+			_, err = validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a secondary VIP from the same IP family", func() {
+			lb.Spec.SecondaryVip = "10.0.0.5"
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.secondaryvip")
+		})
+
+		It("Should deny a secondary VIP already used on the same provider", func() {
+			lb.Spec.SecondaryVip = "2001:db8::2"
+			existing := newLoadBalancer("existing-ipv6-lb")
+			existing.Spec.Vip = "2001:db8::2"
+			Expect(validator.Client.Create(ctx, existing)).To(Succeed())
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.secondaryvip")
+		})
+
+		It("Should admit service ports with target ports and protocols", func() {
+			lb.Spec.Ports = nil
+			lb.Spec.ServicePorts = []lbv1.ServicePort{
+				{Name: "https", Port: 443, TargetPort: 30443},
+				{Name: "dns", Port: 53, Protocol: lbv1.ProtocolUDP},
+				{Name: "dns-tcp", Port: 53, Protocol: lbv1.ProtocolTCP},
+			}
+			_, err := validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny an instance without ports", func() {
+			lb.Spec.Ports = nil
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.serviceports")
+		})
+
+		It("Should deny setting both ports and serviceports", func() {
+			lb.Spec.ServicePorts = []lbv1.ServicePort{{Port: 22623}}
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.ports")
+
+			lb.Spec.Ports = nil
+			_, err = validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny duplicated ports", func() {
+			lb.Spec.Ports = []int{6443, 6443}
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.ports[1]")
+
+			lb.Spec.Ports = nil
+			lb.Spec.ServicePorts = []lbv1.ServicePort{{Port: 6443}, {Port: 6443, Protocol: lbv1.ProtocolTCP}}
+			_, err = validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.serviceports[1].port")
+
+			lb.Spec.ServicePorts = []lbv1.ServicePort{{Name: "api", Port: 6443}, {Name: "api", Port: 22623}}
+			_, err = validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.serviceports[1].name")
+		})
+
+		It("Should deny UDP ports on HAProxy", func() {
+			lb.Spec.Provider.Vendor = "HAProxy"
+			lb.Spec.Ports = nil
+			lb.Spec.ServicePorts = []lbv1.ServicePort{{Port: 53, Protocol: lbv1.ProtocolUDP}}
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.serviceports[0].protocol")
+		})
+
+		It("Should admit a monitor with check parameters", func() {
+			lb.Spec.Monitor.Interval = 10
+			lb.Spec.Monitor.Timeout = 31
+			lb.Spec.Monitor.ExpectedStatus = 200
+			lb.Spec.Monitor.Host = "app.example.com"
+			_, err := validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a monitor timeout lower than the interval on F5", func() {
+			lb.Spec.Provider.Vendor = "F5_BigIP"
+			lb.Spec.Monitor.Interval = 20
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.monitor.timeout")
+		})
+
+		It("Should deny a monitor timeout not lower than the interval on Citrix ADC", func() {
+			lb.Spec.Provider.Vendor = "Citrix_ADC"
+			lb.Spec.Monitor.Interval = 10
+			lb.Spec.Monitor.Timeout = 31
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.monitor.timeout")
+
+			// The Citrix ADC default response timeout is used when it's not set
+			lb.Spec.Monitor.Timeout = 0
+			lb.Spec.Monitor.Interval = 2
+			_, err = validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.monitor.timeout")
+
+			lb.Spec.Monitor.Interval = 10
+			_, err = validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should admit a monitor interval above the timeout on the other vendors", func() {
+			lb.Spec.Monitor.Interval = 20
+			for _, vendor := range []string{"HAProxy", "Citrix_ADC"} {
+				lb.Spec.Provider.Vendor = vendor
+				_, err := validator.ValidateCreate(ctx, lb)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("Should deny the check parameters the vendor doesn't support", func() {
+			lb.Spec.Provider.Vendor = "F5_BigIP"
+			lb.Spec.Monitor.Rise = 2
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.monitor.rise")
+
+			lb.Spec.Monitor.Rise = 0
+			lb.Spec.Monitor.Fall = 3
+			_, err = validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.monitor.fall")
+
+			lb.Spec.Provider.Vendor = "Citrix_ADC"
+			lb.Spec.Monitor.MonitorType = "https"
+			lb.Spec.Monitor.SNI = "app.example.com"
+			_, err = validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.monitor.sni")
+
+			lb.Spec.Monitor.SNI = ""
+			_, err = validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny HTTP check parameters on other monitor types", func() {
+			lb.Spec.Monitor.MonitorType = "icmp"
+			lb.Spec.Monitor.Host = "app.example.com"
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.monitor.host")
+
+			lb.Spec.Monitor.MonitorType = "http"
+			lb.Spec.Monitor.SNI = "app.example.com"
+			_, err = validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.monitor.sni")
+		})
+
+		It("Should admit a tcp monitor without path", func() {
+			lb.Spec.Monitor = lbv1.Monitor{Port: 1883, MonitorType: "tcp", Send: "PING", Receive: "PONG"}
+			_, err := validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a http monitor without path", func() {
+			lb.Spec.Monitor.Path = ""
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.monitor.path")
+
+			lb.Spec.Monitor.Path = "/health"
+			lb.Spec.Monitor.Send = "PING"
+			_, err = validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.monitor.send")
+		})
+
+		It("Should admit the same VIP on a different provider", func() {
+			lb.Spec.Vip = "10.0.0.2"
+			lb.Spec.Provider.Host = "https://5.6.7.8"
+			_, err := validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When referencing a LoadBalancerProvider", func() {
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(lbv1.AddToScheme(scheme)).To(Succeed())
+
+			existing := newLoadBalancer("existing-lb")
+			existing.Spec.Vip = "10.0.0.2"
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "shared-creds", Namespace: "lbconfig"},
+				Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("admin")},
+			}
+			newProvider := func(name string) *lbv1.LoadBalancerProvider {
+				return &lbv1.LoadBalancerProvider{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec: lbv1.LoadBalancerProviderSpec{
+						Vendor: "Dummy",
+						Host:   "https://1.2.3.4",
+						Port:   443,
+						Creds:  lbv1.SecretReference{Name: "shared-creds", Namespace: "lbconfig"},
+					},
+				}
+			}
+			restricted := newProvider("restricted")
+			restricted.Spec.AllowedNamespaces = &metav1.LabelSelector{MatchLabels: map[string]string{"lb": "shared"}}
+			validator = &ExternalLoadBalancerCustomValidator{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing, namespace, secret, newProvider("shared"), restricted).Build(),
+			}
+			lb.Spec.ProviderRef = "shared"
+			lb.Spec.Provider = lbv1.Provider{}
+		})
+
+		It("Should admit an instance using the provider", func() {
+			_, err := validator.ValidateCreate(ctx, lb)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny the connection fields with providerref", func() {
+			lb.Spec.Provider.Host = "https://5.6.7.8"
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.provider.host")
+		})
+
+		It("Should deny vendor options not matching the provider vendor", func() {
+			lb.Spec.Provider.F5 = &lbv1.F5Options{Partition: "Tenant1"}
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.provider.f5")
+		})
+
+		It("Should deny a missing provider", func() {
+			lb.Spec.ProviderRef = "missing"
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.providerref")
+		})
+
+		It("Should deny a provider not allowed in the namespace", func() {
+			lb.Spec.ProviderRef = "restricted"
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.providerref")
+		})
+
+		It("Should deny a VIP already used on the provider host", func() {
+			lb.Spec.Vip = "10.0.0.2"
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.vip")
+			Expect(err.Error()).To(ContainSubstring("existing-lb"))
+		})
+
+		It("Should deny a credentials secret from another namespace without providerref", func() {
+			lb.Spec.ProviderRef = ""
+			lb.Spec.Provider = newLoadBalancer("test-lb").Spec.Provider
+			lb.Spec.Provider.Creds = "lbconfig/shared-creds"
+			_, err := validator.ValidateCreate(ctx, lb)
+			expectInvalid(err, "spec.provider.creds")
+		})
+	})
+
+	Context("When updating an ExternalLoadBalancer", func() {
+		It("Should not conflict with its own VIP", func() {
+			existing := newLoadBalancer("existing-lb")
+			existing.Spec.Vip = "10.0.0.2"
+			updated := existing.DeepCopy()
+			updated.Spec.Ports = []int{6443, 22623}
+			_, err := validator.ValidateUpdate(ctx, existing, updated)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should validate spec changes", func() {
+			updated := lb.DeepCopy()
+			updated.Spec.Provider.Host = "not-an-url"
+			_, err := validator.ValidateUpdate(ctx, lb, updated)
+			expectInvalid(err, "spec.provider.host")
+		})
+
+		It("Should admit metadata changes on an instance whose secret was removed", func() {
+			lb.Spec.Provider.Creds = "missing-secret"
+			updated := lb.DeepCopy()
+			updated.Finalizers = nil
+			_, err := validator.ValidateUpdate(ctx, lb, updated)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})